@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"mayadata.io/e2e-metrics/controller/coverage"
+)
+
+// checkCommand is the name of the sub command that fails if the
+// coverage violates any policy. This is meant to gate merge
+// requests in CI.
+const checkCommand string = "check"
+
+const (
+	// exitMinCoverage indicates coverage below the minimum
+	exitMinCoverage int = 3
+
+	// exitMaxInvalidTests indicates more invalid tests than allowed
+	exitMaxInvalidTests int = 4
+
+	// exitDeprecatedTests indicates tests with deprecated tcid-
+	// prefix
+	exitDeprecatedTests int = 5
+
+	// exitDuplicateTests indicates tests that are planned or
+	// implemented more than once
+	exitDuplicateTests int = 6
+)
+
+// policyExitCodes maps the policy rules to their exit codes
+var policyExitCodes = map[coverage.PolicyRule]int{
+	coverage.PolicyRuleMinCoverage:       exitMinCoverage,
+	coverage.PolicyRuleMaxInvalidTests:   exitMaxInvalidTests,
+	coverage.PolicyRuleNoDeprecatedTests: exitDeprecatedTests,
+	coverage.PolicyRuleNoDuplicateTests:  exitDuplicateTests,
+}
+
+// runCheck runs the check sub command with the given arguments &
+// returns the exit code
+//
+// NOTE:
+//	Exit code is of the first violated rule if more than one rule
+// is violated. Rules are checked in the order of their exit codes.
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	var opts coverageOptions
+	fs := newCoverageFlagSet(checkCommand, &opts, stderr)
+	minCoverage := fs.Float64(
+		"min-coverage",
+		0,
+		"Minimum coverage in percent e.g. 80",
+	)
+	maxInvalidTests := fs.Int(
+		"max-invalid-tests",
+		-1,
+		"Maximum number of tests that are implemented but not planned. "+
+			"Negative value allows any number.",
+	)
+	noDeprecatedTests := fs.Bool(
+		"no-deprecated-tests",
+		false,
+		"Fail if any test uses the deprecated tcid- prefix",
+	)
+	noDuplicateTests := fs.Bool(
+		"no-duplicate-tests",
+		false,
+		"Fail if any test is planned or implemented more than once",
+	)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *minCoverage < 0 || *minCoverage > 100 {
+		fmt.Fprintf(stderr, "Invalid min-coverage %v: want 0 to 100\n", *minCoverage)
+		return exitUsage
+	}
+	policy := coverage.Policy{
+		MinCoverage:       *minCoverage,
+		MaxInvalidTests:   *maxInvalidTests,
+		NoDeprecatedTests: *noDeprecatedTests,
+		NoDuplicateTests:  *noDuplicateTests,
+	}
+
+	summary, err := calculateCoverage(&opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to calculate coverage: %v\n", err)
+		return exitError
+	}
+	err = summary.WriteText(stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to print coverage: %v\n", err)
+		return exitError
+	}
+	violations := policy.Check(summary)
+	if len(violations) == 0 {
+		fmt.Fprintln(stdout, "Policy violations: 0")
+		return exitOK
+	}
+	fmt.Fprintf(stdout, "Policy violations: %d\n", len(violations))
+	for _, violation := range violations {
+		fmt.Fprintf(stdout, "  %s\n", violation)
+	}
+	return policyExitCodes[violations[0].Rule]
+}
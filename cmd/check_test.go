@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunCheck(t *testing.T) {
+	var source = []string{
+		"--path", "../config/testdata",
+		"--plan", ".master-plan.yml",
+		"--plan", "masterplan=plans/*.yml",
+		"--ci", ".gitlab-ci.yml",
+	}
+	var tests = map[string]struct {
+		args             []string
+		expectCode       int
+		expectViolations []string
+	}{
+		"no policy": {
+			expectCode: exitOK,
+		},
+		"coverage met": {
+			args:       []string{"--min-coverage", "50", "--max-invalid-tests", "0"},
+			expectCode: exitOK,
+		},
+		"coverage below minimum": {
+			args:       []string{"--min-coverage", "80"},
+			expectCode: exitMinCoverage,
+			expectViolations: []string{
+				"MinCoverage: Coverage 50% (2/4) is below 80%",
+			},
+		},
+		"deprecated tests": {
+			args:       []string{"--no-deprecated-tests"},
+			expectCode: exitDeprecatedTests,
+			expectViolations: []string{
+				"NoDeprecatedTests: 2 tests use the deprecated tcid- prefix",
+			},
+		},
+		"duplicate tests": {
+			args:       []string{"--no-duplicate-tests"},
+			expectCode: exitDuplicateTests,
+			expectViolations: []string{
+				"NoDuplicateTests: 1 tests",
+			},
+		},
+		"first violated rule decides exit code": {
+			args:       []string{"--no-deprecated-tests", "--min-coverage", "60"},
+			expectCode: exitMinCoverage,
+			expectViolations: []string{
+				"MinCoverage: Coverage 50% (2/4) is below 60%",
+				"NoDeprecatedTests: 2 tests use the deprecated tcid- prefix",
+			},
+		},
+		"invalid min coverage": {
+			args:       []string{"--min-coverage", "120"},
+			expectCode: exitUsage,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			got := runCheck(append(source, mock.args...), &stdout, &stderr)
+			if got != mock.expectCode {
+				t.Fatalf(
+					"Expected exit code %d got %d: %s", mock.expectCode, got, stderr.String(),
+				)
+			}
+			if mock.expectCode == exitUsage {
+				return
+			}
+			var gotViolations []string
+			for _, line := range printedViolations(t, stdout.String()) {
+				gotViolations = append(gotViolations, strings.TrimSpace(line))
+			}
+			if len(gotViolations) != len(mock.expectViolations) {
+				t.Fatalf(
+					"Expected violations %q got %q", mock.expectViolations, gotViolations,
+				)
+			}
+			for i, expect := range mock.expectViolations {
+				if !strings.HasPrefix(gotViolations[i], expect) {
+					t.Fatalf(
+						"Expected violation %q got %q", expect, gotViolations[i],
+					)
+				}
+			}
+		})
+	}
+}
+
+// printedViolations returns the violations printed by the check sub
+// command
+func printedViolations(t *testing.T, stdout string) []string {
+	parts := strings.SplitN(stdout, "Policy violations: ", 2)
+	if len(parts) != 2 {
+		t.Fatalf("Expected policy violations got %q", stdout)
+	}
+	lines := strings.Split(strings.TrimSpace(parts[1]), "\n")
+	return lines[1:]
+}
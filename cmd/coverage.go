@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"mayadata.io/e2e-metrics/config"
+	"mayadata.io/e2e-metrics/controller/coverage"
+	"mayadata.io/e2e-metrics/metrics"
+	logf "mayadata.io/e2e-metrics/pkg/logs"
+	"mayadata.io/e2e-metrics/report"
+)
+
+const (
+	// exitOK indicates a successful run
+	exitOK int = 0
+
+	// exitError indicates a failure to calculate the coverage
+	exitError int = 1
+
+	// exitUsage indicates invalid command line arguments
+	exitUsage int = 2
+)
+
+// coverageCommand is the name of the sub command that calculates
+// the coverage without Kubernetes
+const coverageCommand string = "coverage"
+
+// formatText prints the coverage in a human readable form
+const formatText string = "text"
+
+// coverageOptions has the command line options of the coverage
+// sub command
+type coverageOptions struct {
+	path     string
+	gitRef   string
+	taxonomy string
+	plan     config.PathSourceConfigs
+	ci       config.PathSourceConfigs
+}
+
+// newCoverageFlagSet returns the flags of the coverage sub command
+func newCoverageFlagSet(name string, opts *coverageOptions, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(
+		&opts.path,
+		"path",
+		".",
+		"The directory that the plan & ci files are relative to",
+	)
+	fs.StringVar(
+		&opts.gitRef,
+		"git-ref",
+		"",
+		"Read the files from this branch, tag or commit of the git repository at path",
+	)
+	fs.StringVar(
+		&opts.taxonomy,
+		"taxonomy",
+		"",
+		"The file that has the allowed values of test cases. Undeclared values are set as warnings.",
+	)
+	opts.plan.Format = config.FormatMasterPlan
+	fs.Var(
+		&opts.plan,
+		"plan",
+		fmt.Sprintf(
+			"Source of planned test cases as path or format=path. "+
+				"Format is one of %v & defaults to %s. Can be repeated.",
+			config.DesiredSourceFormats(),
+			config.FormatMasterPlan,
+		),
+	)
+	opts.ci.Format = config.FormatGitlabCI
+	fs.Var(
+		&opts.ci,
+		"ci",
+		fmt.Sprintf(
+			"Source of implemented test cases as path or format=path. "+
+				"Format is one of %v & defaults to %s. Can be repeated.",
+			config.ActualSourceFormats(),
+			config.FormatGitlabCI,
+		),
+	)
+	return fs
+}
+
+// calculateCoverage returns the coverage based on the given options
+func calculateCoverage(opts *coverageOptions) (*coverage.Summary, error) {
+	log := logf.Log.WithName(coverageCommand)
+	taxonomy := config.DefaultTaxonomy()
+	if opts.taxonomy != "" {
+		var err error
+		taxonomy, err = config.LoadTaxonomy(opts.taxonomy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	reconciler := coverage.NewReconciler(coverage.ReconcilerConfig{
+		Log: log,
+		Prom: metrics.NewFromConfig(metrics.Config{
+			Log:                 log,
+			TestCountDimensions: taxonomy.DimensionNames(),
+		}),
+		ConfigPath:     opts.path,
+		GitRef:         opts.gitRef,
+		DesiredSources: opts.plan.SourceConfigs,
+		ActualSources:  opts.ci.SourceConfigs,
+		Taxonomy:       taxonomy,
+	})
+	return reconciler.Calculate()
+}
+
+// runCoverage runs the coverage sub command with the given arguments
+// & returns the exit code
+//
+// NOTE:
+//	Default sources are used if plan or ci is not set
+func runCoverage(args []string, stdout, stderr io.Writer) int {
+	var opts coverageOptions
+	fs := newCoverageFlagSet(coverageCommand, &opts, stderr)
+	format := fs.String(
+		"format",
+		formatText,
+		fmt.Sprintf(
+			"Format to print the coverage in. Format is one of %v.",
+			append([]string{formatText}, report.Formats()...),
+		),
+	)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *format != formatText && !report.IsFormat(*format) {
+		fmt.Fprintf(
+			stderr,
+			"Unsupported format %q: want one of %v\n",
+			*format,
+			append([]string{formatText}, report.Formats()...),
+		)
+		return exitUsage
+	}
+	summary, err := calculateCoverage(&opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to calculate coverage: %v\n", err)
+		return exitError
+	}
+	if *format == formatText {
+		err = summary.WriteText(stdout)
+	} else {
+		err = report.Write(stdout, *format, summary.Report())
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to print coverage: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
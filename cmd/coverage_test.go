@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRunCoverage(t *testing.T) {
+	var tests = map[string]struct {
+		args         []string
+		expectCode   int
+		expectStdout string
+	}{
+		"plan & ci": {
+			args: []string{
+				"--path", "../config/testdata",
+				"--plan", ".master-plan.yml",
+				"--plan", "masterplan=plans/*.yml",
+				"--ci", ".gitlab-ci.yml",
+			},
+			expectCode: exitOK,
+			expectStdout: `Valid tests: 2
+  TCID-DIR-HEALTH-CHECK
+  TCID-DIR-HEALTH-CHECK-V2
+Invalid tests: 0
+Missing tests: 2
+  TCID-DIR-INSTALL-ON-LOCAL-PV
+  TCID-OPENEBS-UPGRADE
+Deprecated tests: 2
+  tcid-DIR-HEALTH-CHECK
+  tcid-dir-health-check-v2
+Duplicate tests: 1
+  TCID-DIR-HEALTH-CHECK
+Coverage: 50% (2/4)
+`,
+		},
+		"csv format": {
+			args: []string{
+				"--path", "../config/testdata",
+				"--plan", ".master-plan.yml",
+				"--ci", ".gitlab-ci.yml",
+				"--format", "csv",
+			},
+			expectCode: exitOK,
+			expectStdout: `tcid,state
+TCID-DIR-HEALTH-CHECK,valid
+TCID-DIR-HEALTH-CHECK-V2,valid
+TCID-DIR-INSTALL-ON-LOCAL-PV,missing
+tcid-DIR-HEALTH-CHECK,deprecated
+tcid-dir-health-check-v2,deprecated
+`,
+		},
+		"unsupported format": {
+			args:       []string{"--format", "pdf"},
+			expectCode: exitUsage,
+		},
+		"missing file": {
+			args: []string{
+				"--path", "../config/testdata",
+				"--ci", "missing.yml",
+			},
+			expectCode: exitError,
+		},
+		"invalid flag": {
+			args:       []string{"--invalid"},
+			expectCode: exitUsage,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			got := runCoverage(mock.args, &stdout, &stderr)
+			if got != mock.expectCode {
+				t.Fatalf(
+					"Expected exit code %d got %d: %s", mock.expectCode, got, stderr.String(),
+				)
+			}
+			if stdout.String() != mock.expectStdout {
+				t.Fatalf(
+					"Expected no diff got\n%s", cmp.Diff(mock.expectStdout, stdout.String()),
+				)
+			}
+		})
+	}
+}
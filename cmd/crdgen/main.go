@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// crdgen generates the custom resource definitions of e2e-metrics
+// from its Go API types
+//
+// NOTE:
+//	Run 'make manifests' after changing the API types. A test in
+// pkg/crd fails if deploy/crd.yaml is not regenerated.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"mayadata.io/e2e-metrics/pkg/crd"
+)
+
+var output = flag.String(
+	"o",
+	"",
+	"File to write the custom resource definitions to. Defaults to stdout.",
+)
+
+func main() {
+	flag.Parse()
+
+	data, err := crd.Generate()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate crds: %v\n", err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	err = ioutil.WriteFile(*output, data, 0644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write crds: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+)
+
+// eventComponent is the source of the events recorded by this
+// operator
+const eventComponent string = "e2e-metrics"
+
+// newEventRecorder returns a recorder that records events to the
+// Kubernetes cluster that metac is connected to
+//
+// NOTE:
+//	Events with the same object, reason & message are aggregated
+// by the recorder into a single event with an incremented count
+func newEventRecorder(log logr.Logger) (record.EventRecorder, error) {
+	// kubeconfig flag is registered by metac
+	var kubeconfig string
+	if f := flag.Lookup("client-config-path"); f != nil {
+		kubeconfig = f.Value.String()
+	}
+	var config *rest.Config
+	var err error
+	if kubeconfig != "" {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	} else {
+		config, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get kubernetes config")
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create kubernetes client")
+	}
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(func(format string, args ...interface{}) {
+		log.V(4).Info("Recorded event", "event", fmt.Sprintf(format, args...))
+	})
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+		Interface: client.CoreV1().Events(""),
+	})
+	return broadcaster.NewRecorder(
+		scheme.Scheme,
+		corev1.EventSource{Component: eventComponent},
+	), nil
+}
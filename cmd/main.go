@@ -19,17 +19,21 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
 	"sync"
 
 	"openebs.io/metac/controller/generic"
 	"openebs.io/metac/start"
 
+	"mayadata.io/e2e-metrics/config"
 	"mayadata.io/e2e-metrics/controller/coverage"
 	"mayadata.io/e2e-metrics/metrics"
 	ctx "mayadata.io/e2e-metrics/pkg/context"
 	logf "mayadata.io/e2e-metrics/pkg/logs"
 	"mayadata.io/e2e-metrics/pkg/signal"
+	"mayadata.io/e2e-metrics/pkg/watch"
+	"mayadata.io/e2e-metrics/report"
 )
 
 var (
@@ -38,8 +42,68 @@ var (
 		":9898",
 		"The address to bind the http endpoint to be scraped by prometheus",
 	)
+
+	configPath = flag.String(
+		"config-path",
+		coverage.DefaultConfigPath,
+		"The directory that has the config files e.g. .master-plan.yml",
+	)
+
+	watchConfig = flag.Bool(
+		"watch-config",
+		true,
+		"Reload the config files & reconcile when these change",
+	)
+
+	reportDir = flag.String(
+		"report-dir",
+		"",
+		"The directory to write the report of each PipelineCoverage to. Reports are not written if this is not set.",
+	)
+
+	taxonomyPath = flag.String(
+		"taxonomy-path",
+		"",
+		"The file that has the dimensions of test count metrics & their allowed values. "+
+			"Dimensions component, feature & kind with any value are used if this is not set. "+
+			"This is loaded once at startup. Hence, changes need a restart.",
+	)
+
+	desiredSources config.SourceConfigs
+	actualSources  config.SourceConfigs
+	reportFormats  report.FormatList
 )
 
+func init() {
+	flag.Var(
+		&desiredSources,
+		"desired-source",
+		fmt.Sprintf(
+			"Source of planned test cases as format=path e.g. masterplan=.master-plan.yml. "+
+				"Format is one of %v. Can be repeated to combine several sources.",
+			config.DesiredSourceFormats(),
+		),
+	)
+	flag.Var(
+		&actualSources,
+		"actual-source",
+		fmt.Sprintf(
+			"Source of implemented test cases as format=path e.g. gitlabci=.gitlab-ci.yml. "+
+				"Format is one of %v. Can be repeated to combine several sources.",
+			config.ActualSourceFormats(),
+		),
+	)
+	flag.Var(
+		&reportFormats,
+		"report-format",
+		fmt.Sprintf(
+			"Format of the reports written to report-dir. "+
+				"Format is one of %v. Can be repeated & defaults to all the formats.",
+			report.Formats(),
+		),
+	)
+}
+
 // main function is the entry point of this binary.
 //
 // This registers various controller (i.e. kubernetes reconciler)
@@ -54,7 +118,19 @@ var (
 // NOTE:
 //	One can consider each registered function as an independent
 // kubernetes controller & this project as the operator.
+//
+// NOTE:
+//	'e2e-metrics coverage' calculates the coverage of local files
+// without Kubernetes & exits. 'e2e-metrics check' does the same &
+// exits with a non zero code if any policy is violated.
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == coverageCommand {
+		os.Exit(runCoverage(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == checkCommand {
+		os.Exit(runCheck(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	logf.InitLogs()
 	defer logf.FlushLogs()
 
@@ -63,7 +139,22 @@ func main() {
 	rootCtx = logf.NewContext(rootCtx, nil, "operator")
 	log := logf.FromContext(rootCtx)
 
-	m := metrics.New(log)
+	// metrics are registered with the dimensions of the taxonomy.
+	// Hence, the taxonomy is not reloaded while running.
+	taxonomy := config.DefaultTaxonomy()
+	if *taxonomyPath != "" {
+		var err error
+		taxonomy, err = config.LoadTaxonomy(*taxonomyPath)
+		if err != nil {
+			log.Error(err, "failed to load taxonomy", "path", *taxonomyPath)
+			os.Exit(1)
+		}
+	}
+
+	m := metrics.NewFromConfig(metrics.Config{
+		Log:                 log,
+		TestCountDimensions: taxonomy.DimensionNames(),
+	})
 	mserver, err := m.Start(*metricsAddr)
 	if err != nil {
 		log.Error(
@@ -75,9 +166,53 @@ func main() {
 		os.Exit(1)
 	}
 
-	syncer := coverage.NewSyncer(log, m)
+	if *reportDir != "" {
+		err = os.MkdirAll(*reportDir, 0755)
+		if err != nil {
+			log.Error(err, "failed to create report directory", "path", *reportDir)
+			os.Exit(1)
+		}
+		if len(reportFormats) == 0 {
+			reportFormats = report.Formats()
+		}
+	}
+
+	recorder, err := newEventRecorder(log)
+	if err != nil {
+		// coverage continues to be computed without any events
+		log.Error(err, "failed to create event recorder")
+	}
+
+	syncer := coverage.NewSyncer(coverage.SyncerConfig{
+		Log:            log,
+		Prom:           m,
+		ConfigPath:     *configPath,
+		DesiredSources: desiredSources,
+		ActualSources:  actualSources,
+		Taxonomy:       taxonomy,
+		ReportDir:      *reportDir,
+		ReportFormats:  reportFormats,
+		Recorder:       recorder,
+	})
 	generic.AddToInlineRegistry("sync/pipelinecoverage", syncer.Sync)
 
+	if *watchConfig {
+		watcher := watch.New(watch.Config{
+			Log:  log,
+			Path: *configPath,
+			OnChange: func() {
+				syncer.Resync()
+			},
+		})
+		go func() {
+			err := watcher.Start(stopCh)
+			if err != nil {
+				// coverage continues to be computed during syncs
+				log.Error(err, "failed to watch config", "path", *configPath)
+			}
+		}()
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
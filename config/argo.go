@@ -0,0 +1,277 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FormatArgo is the source format of argo workflow files
+	FormatArgo string = "argo"
+
+	// KindArgoWorkflow is the kind of argo workflow resource
+	KindArgoWorkflow string = "Workflow"
+
+	// KindArgoWorkflowTemplate is the kind of argo workflow
+	// template resource
+	KindArgoWorkflowTemplate string = "WorkflowTemplate"
+
+	// KindArgoClusterWorkflowTemplate is the kind of argo cluster
+	// workflow template resource
+	KindArgoClusterWorkflowTemplate string = "ClusterWorkflowTemplate"
+
+	// KindArgoCronWorkflow is the kind of argo cron workflow
+	// resource
+	KindArgoCronWorkflow string = "CronWorkflow"
+)
+
+func init() {
+	RegisterActualSource(FormatArgo, NewArgoSource)
+}
+
+// ArgoWorkflow is the typed representation of argo workflow as
+// well as argo workflow templates
+//
+// NOTE:
+//	A sample workflow looks like below:
+//
+//	apiVersion: argoproj.io/v1alpha1
+//	kind: Workflow
+//	metadata:
+//	  generateName: e2e-
+//	spec:
+//	  entrypoint: e2e
+//	  templates:
+//	  - name: e2e
+//	    steps:
+//	    - - name: TCID-DIR-HEALTH-CHECK
+//	        template: health-check
+//	  - name: health-check
+//	    metadata:
+//	      labels:
+//	        e2e.mayadata.io/tcid: TCID-DIR-HEALTH-CHECK
+type ArgoWorkflow struct {
+	Metadata KubeObjectMeta   `yaml:"metadata"`
+	Spec     ArgoWorkflowSpec `yaml:"spec"`
+}
+
+// ArgoCronWorkflow is the typed representation of argo cron
+// workflow
+type ArgoCronWorkflow struct {
+	Metadata KubeObjectMeta `yaml:"metadata"`
+	Spec     struct {
+		WorkflowSpec ArgoWorkflowSpec `yaml:"workflowSpec"`
+	} `yaml:"spec"`
+}
+
+// ArgoWorkflowSpec has the templates of an argo workflow
+type ArgoWorkflowSpec struct {
+	Entrypoint string         `yaml:"entrypoint"`
+	Templates  []ArgoTemplate `yaml:"templates"`
+}
+
+// ArgoTemplate is one of the templates of an argo workflow
+type ArgoTemplate struct {
+	// Line is the line where this template is declared
+	Line int `yaml:"-"`
+
+	Name     string         `yaml:"name"`
+	Metadata KubeObjectMeta `yaml:"metadata"`
+
+	// Steps are run as a list of parallel steps
+	Steps [][]ArgoWorkflowStep `yaml:"steps"`
+
+	DAG *ArgoDAG `yaml:"dag"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (t *ArgoTemplate) UnmarshalYAML(node *yaml.Node) error {
+	type plain ArgoTemplate
+	err := node.Decode((*plain)(t))
+	if err != nil {
+		return err
+	}
+	t.Line = node.Line
+	return nil
+}
+
+// ArgoDAG has the tasks of a template that are run as a directed
+// acyclic graph
+type ArgoDAG struct {
+	Tasks []ArgoWorkflowStep `yaml:"tasks"`
+}
+
+// ArgoWorkflowStep is a step or a dag task that invokes a template
+type ArgoWorkflowStep struct {
+	// Line is the line where this step is declared
+	Line int `yaml:"-"`
+
+	Name        string           `yaml:"name"`
+	Template    string           `yaml:"template"`
+	TemplateRef *ArgoTemplateRef `yaml:"templateRef"`
+}
+
+// ArgoTemplateRef refers to a template of a workflow template
+type ArgoTemplateRef struct {
+	// Name is the name of the workflow template
+	Name string `yaml:"name"`
+
+	// Template is the name of the template
+	Template string `yaml:"template"`
+
+	// ClusterScope is true if the workflow template is a cluster
+	// workflow template
+	ClusterScope bool `yaml:"clusterScope"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (s *ArgoWorkflowStep) UnmarshalYAML(node *yaml.Node) error {
+	type plain ArgoWorkflowStep
+	err := node.Decode((*plain)(s))
+	if err != nil {
+		return err
+	}
+	s.Line = node.Line
+	return nil
+}
+
+// ArgoSource loads the actual test cases from argo workflows &
+// workflow templates
+type ArgoSource struct {
+	SourceConfig
+}
+
+// NewArgoSource returns a new instance of ArgoSource
+func NewArgoSource(conf SourceConfig) (ActualSource, error) {
+	return &ArgoSource{
+		SourceConfig: conf,
+	}, nil
+}
+
+// argoWorkflowResource is a resource that has an argo workflow spec
+type argoWorkflowResource struct {
+	resource *kubeResource
+	spec     ArgoWorkflowSpec
+}
+
+// templateKey returns the key of the template with the given name
+// that is declared in this resource
+func (r *argoWorkflowResource) templateKey(name string) string {
+	return r.resource.String() + "/" + name
+}
+
+// refKey returns the key of the template that is invoked by the
+// given step
+func (r *argoWorkflowResource) refKey(step ArgoWorkflowStep) string {
+	if step.TemplateRef == nil {
+		return r.templateKey(step.Template)
+	}
+	kind := KindArgoWorkflowTemplate
+	if step.TemplateRef.ClusterScope {
+		kind = KindArgoClusterWorkflowTemplate
+	}
+	return kind + "/" + step.TemplateRef.Name + "/" + step.TemplateRef.Template
+}
+
+// LoadActual implements ActualSource interface
+//
+// NOTE:
+//	Templates that are either named with the test case id or
+// labelled with TestCaseIDLabelKey are considered as test cases.
+// Steps & dag tasks named with the test case id are test cases as
+// well. A step is also labelled if the template it invokes is
+// labelled. Such a step is not considered since the template it
+// invokes is the test case. Templates may be invoked from other
+// files via templateRef. Resources of other kinds are ignored.
+func (s *ArgoSource) LoadActual() ([]ActualTestCase, error) {
+	resources, err := loadKubeResources(s.SourceConfig)
+	if err != nil {
+		return nil, err
+	}
+	var workflows []*argoWorkflowResource
+	for _, resource := range resources {
+		var spec ArgoWorkflowSpec
+		switch resource.Kind {
+		case KindArgoWorkflow, KindArgoWorkflowTemplate, KindArgoClusterWorkflowTemplate:
+			var workflow ArgoWorkflow
+			err = resource.decode(&workflow)
+			spec = workflow.Spec
+		case KindArgoCronWorkflow:
+			var cron ArgoCronWorkflow
+			err = resource.decode(&cron)
+			spec = cron.Spec.WorkflowSpec
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		workflows = append(workflows, &argoWorkflowResource{
+			resource: resource,
+			spec:     spec,
+		})
+	}
+	var tests []ActualTestCase
+	var add = func(workflow *argoWorkflowResource, test ActualTestCase, line int) {
+		test.Format = FormatArgo
+		test.File = workflow.resource.File
+		test.Line = line
+		test.Resource = workflow.resource.String()
+		tests = append(tests, test)
+	}
+	// labels of templates are looked up by steps that invoke them
+	var templateLabels = map[string]map[string]string{}
+	// test case ids of templates that are test cases
+	var templateTCIDs = map[string]string{}
+	for _, workflow := range workflows {
+		for _, template := range workflow.spec.Templates {
+			key := workflow.templateKey(template.Name)
+			templateLabels[key] = template.Metadata.Labels
+			test, ok := newLabelledActualTestCase(template.Name, template.Metadata.Labels)
+			if !ok {
+				continue
+			}
+			templateTCIDs[key] = test.TCID
+			add(workflow, test, template.Line)
+		}
+	}
+	for _, workflow := range workflows {
+		for _, template := range workflow.spec.Templates {
+			var steps []ArgoWorkflowStep
+			for _, parallel := range template.Steps {
+				steps = append(steps, parallel...)
+			}
+			if template.DAG != nil {
+				steps = append(steps, template.DAG.Tasks...)
+			}
+			for _, step := range steps {
+				ref := workflow.refKey(step)
+				test, ok := newLabelledActualTestCase(step.Name, templateLabels[ref])
+				if !ok {
+					continue
+				}
+				if tcid, found := templateTCIDs[ref]; found && tcid == test.TCID {
+					// invoked template is already registered
+					continue
+				}
+				add(workflow, test, step.Line)
+			}
+		}
+	}
+	return tests, nil
+}
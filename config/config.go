@@ -17,16 +17,16 @@ limitations under the License.
 package config
 
 import (
-	"bufio"
-	"bytes"
-	"io"
 	"io/ioutil"
 	"os"
+	"path"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
-	"k8s.io/klog/v2"
 
 	prom "mayadata.io/e2e-metrics/metrics"
 )
@@ -54,31 +54,150 @@ const (
 	//	- miot1x is the tcid value
 	//	- maya-io-server-check is the testcase name
 	ActualTestCaseNameDelimiter string = "-"
+)
 
-	// DesiredTestCaseNamePrefix refers to the prefix associated with
-	// every test case name
-	//
-	// NOTE:
-	//	Desired test cases are ones whose testcases are found at
-	// .master-plan.yml
-	DesiredTestCaseNamePrefix string = "- tcid:"
+// ActualTestCase has the details of an implemented test case
+type ActualTestCase struct {
+	TCID string
 
-	// DesiredTestCaseNameDelimiter is the delimiter used to separate
-	// the testcase name from its prefix & test case id value
-	//
-	// NOTE:
-	//	tcid: miot1x is the yaml line where:
-	//	- tcid is the prefix
-	//	- miot1x is the tcid value
-	DesiredTestCaseNameDelimiter string = ": "
-)
+	// Format is the format of the source that has this test case
+	Format string
+
+	// File is the config file where this test case is implemented
+	File string
+
+	// Line is the line in File where this test case is declared
+	Line int
+
+	// Name is the name of the test that implements this test case
+	// if it differs from the test case id e.g. ginkgo spec text
+	Name string
+
+	// ImplementationType is the framework this test case is
+	// implemented with. It defaults to litmus.
+	ImplementationType prom.TestImplementationType
+
+	// Deprecated is true if this test case is named with the
+	// deprecated test case id prefix
+	Deprecated bool
+
+	// Job is the gitlab ci job that implements this test case
+	Job *GitlabCIJob
+
+	// Resource is the kind & name of the kubernetes resource that
+	// implements this test case e.g. Pipeline/e2e
+	Resource string
+
+	// WorkflowJob is the github actions job that implements this
+	// test case
+	WorkflowJob *GithubWorkflowJob
+
+	// Matrix is the matrix combination of WorkflowJob that runs
+	// this test case
+	Matrix map[string]interface{}
+}
+
+// unit returns the unit that implements this test case. A test case
+// that is found more than once in the same unit is not a duplicate.
+//
+// NOTE:
+//	Unit is the job of a gitlab ci or github actions file, the
+// resource of a kubernetes file & the directory of other files e.g.
+// a litmus experiment that has a litmusbook & its ansible playbook
+// or a go package that has ginkgo specs & their test function.
+func (t ActualTestCase) unit() string {
+	switch {
+	case t.Job != nil:
+		return t.Format + ":" + t.File + "#" + t.Job.Name
+	case t.WorkflowJob != nil:
+		return t.Format + ":" + t.File + "#" + t.WorkflowJob.ID
+	case t.Resource != "":
+		return t.Format + ":" + t.File + "#" + t.Resource
+	}
+	return t.Format + ":" + path.Dir(t.File)
+}
 
 // TestCasesMetrics has required details on actual vs. desired
 // e2e test cases
 type TestCasesMetrics struct {
-	DesiredTestCases    map[string]bool
-	ActualTestCases     map[string]bool
-	DeprecatedTestCases []string
+	DesiredTestCases map[string]PlannedTest
+	ActualTestCases  map[string]ActualTestCase
+
+	// DeprecatedTestCases are implemented with the deprecated test
+	// case id prefix. These are keyed by their test case id. Hence,
+	// a test case implemented by more than one job is found once.
+	DeprecatedTestCases map[string]ActualTestCase
+
+	// DuplicateTestCases are either planned more than once or are
+	// implemented by more than one job
+	DuplicateTestCases []string
+
+	// UndeclaredValues are the values of test cases that are not
+	// declared in the taxonomy
+	UndeclaredValues []UndeclaredValue
+
+	// Commit is the SHA of the git commit that the test cases are
+	// loaded from. It is empty if these are not loaded from git.
+	Commit string
+}
+
+// DeprecatedTCIDs returns the sorted test case ids of the deprecated
+// test cases
+func (tcm *TestCasesMetrics) DeprecatedTCIDs() []string {
+	var tcids []string
+	for tcid := range tcm.DeprecatedTestCases {
+		tcids = append(tcids, tcid)
+	}
+	sort.Strings(tcids)
+	return tcids
+}
+
+const (
+	// DefaultDesiredTestCasesFileName is the file that has all the
+	// desired test cases
+	DefaultDesiredTestCasesFileName string = ".master-plan.yml"
+
+	// DefaultActualTestCasesFileName is the file that has the
+	// implemented test cases
+	DefaultActualTestCasesFileName string = ".gitlab-ci.yml"
+
+	// DefaultGithubWorkflowsDir is the directory that has the
+	// github actions workflows with implemented test cases
+	DefaultGithubWorkflowsDir string = ".github/workflows"
+)
+
+// DefaultDesiredSources returns the desired sources that are used
+// when none are configured
+func DefaultDesiredSources() []SourceConfig {
+	return []SourceConfig{
+		{
+			Format:   FormatMasterPlan,
+			Path:     DefaultDesiredTestCasesFileName,
+			Optional: true,
+		},
+	}
+}
+
+// DefaultActualSources returns the actual sources that are used
+// when none are configured
+func DefaultActualSources() []SourceConfig {
+	return []SourceConfig{
+		{
+			Format:   FormatGitlabCI,
+			Path:     DefaultActualTestCasesFileName,
+			Optional: true,
+		},
+		{
+			Format:   FormatGithubActions,
+			Path:     DefaultGithubWorkflowsDir + "/*.yml",
+			Optional: true,
+		},
+		{
+			Format:   FormatGithubActions,
+			Path:     DefaultGithubWorkflowsDir + "/*.yaml",
+			Optional: true,
+		},
+	}
 }
 
 // Loadable helps loading the testcase config files
@@ -87,27 +206,79 @@ type Loadable struct {
 	prom *prom.Metrics
 	Path string
 
-	// File that has all the desired test cases
-	DesiredTestCasesFileName string
+	// IncludePath is the directory that local includes of gitlab
+	// ci config are resolved against. This can be the path of a
+	// local checkout of the repository. It defaults to Path.
+	IncludePath string
+
+	// GitRef when set reads the files from this ref of the git
+	// repository found at Path. Ref can be a branch, a tag or a
+	// commit SHA e.g. HEAD, master, v1.0.0
+	GitRef string
+
+	// Sources that have all the desired test cases
+	DesiredSources []SourceConfig
+
+	// Sources that have the implemented test cases
+	ActualSources []SourceConfig
 
-	// File that has the implemented test cases
-	ActualTestCasesFileName string
+	// Taxonomy has the dimensions of test count metrics & their
+	// allowed values
+	Taxonomy *Taxonomy
+
+	// PipelineCoverage is the name of the PipelineCoverage that the
+	// test count metrics are set for
+	PipelineCoverage string
+
+	// Namespace is the namespace of the PipelineCoverage. This is
+	// set in the source load metrics along with its name.
+	Namespace string
 }
 
 type LoadableConfig struct {
-	Log  logr.Logger
-	Prom *prom.Metrics
-	Path string
+	Log            logr.Logger
+	Prom           *prom.Metrics
+	Path           string
+	IncludePath    string
+	GitRef         string
+	DesiredSources []SourceConfig
+	ActualSources  []SourceConfig
+	Taxonomy       *Taxonomy
+
+	PipelineCoverage string
+	Namespace        string
 }
 
 // New returns a new instance of config
 func New(conf LoadableConfig) *Loadable {
+	includePath := conf.IncludePath
+	if includePath == "" {
+		includePath = conf.Path
+	}
+	desiredSources := conf.DesiredSources
+	if len(desiredSources) == 0 {
+		desiredSources = DefaultDesiredSources()
+	}
+	actualSources := conf.ActualSources
+	if len(actualSources) == 0 {
+		actualSources = DefaultActualSources()
+	}
+	taxonomy := conf.Taxonomy
+	if taxonomy == nil {
+		taxonomy = DefaultTaxonomy()
+	}
 	return &Loadable{
-		Path:                     conf.Path,
-		log:                      conf.Log,
-		prom:                     conf.Prom,
-		DesiredTestCasesFileName: ".master-plan.yml",
-		ActualTestCasesFileName:  ".gitlab-ci.yml",
+		Path:           conf.Path,
+		IncludePath:    includePath,
+		GitRef:         conf.GitRef,
+		log:            conf.Log,
+		prom:           conf.Prom,
+		DesiredSources: desiredSources,
+		ActualSources:  actualSources,
+		Taxonomy:       taxonomy,
+
+		PipelineCoverage: conf.PipelineCoverage,
+		Namespace:        conf.Namespace,
 	}
 }
 
@@ -118,136 +289,147 @@ func (c *Loadable) LoadOrEmpty() (*TestCasesMetrics, error) {
 	if err != nil {
 		// set an empty metrics if error
 		mc = &TestCasesMetrics{
-			DesiredTestCases: map[string]bool{},
-			ActualTestCases:  map[string]bool{},
+			DesiredTestCases:    map[string]PlannedTest{},
+			ActualTestCases:     map[string]ActualTestCase{},
+			DeprecatedTestCases: map[string]ActualTestCase{},
 		}
 	}
 	return mc, err
 }
 
+// withDefaultFiles sets the readers of the given source config
+// if they are not set
+func withDefaultFiles(conf SourceConfig, files, includeFiles FileReader) SourceConfig {
+	if conf.Files == nil {
+		conf.Files = files
+	}
+	if conf.IncludeFiles == nil {
+		conf.IncludeFiles = includeFiles
+	}
+	return conf
+}
+
+// defaultFiles returns the readers of the files & the included
+// files that are used by the sources without any reader. Both read
+// from the given ref if GitRef is set.
+func (c *Loadable) defaultFiles() (FileReader, FileReader, string, error) {
+	if c.GitRef == "" {
+		return NewDirReader(c.Path), NewDirReader(c.IncludePath), "", nil
+	}
+	files, err := NewGitReader(c.Path, c.GitRef)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return files, files, files.Commit, nil
+}
+
 // Load loads all config files or load error
 func (c *Loadable) Load() (*TestCasesMetrics, error) {
 	log := c.log
 	log.V(3).Info("Will load test case config(s)", "path", c.Path)
 
-	files, readDirErr := ioutil.ReadDir(c.Path)
-	if readDirErr != nil {
-		return nil, readDirErr
-	}
-	if len(files) == 0 {
-		return nil,
-			errors.Errorf("No config(s) found at %q", c.Path)
+	if c.Path != "" && c.GitRef == "" {
+		files, readDirErr := ioutil.ReadDir(c.Path)
+		if readDirErr != nil {
+			return nil, readDirErr
+		}
+		if len(files) == 0 {
+			return nil,
+				errors.Errorf("No config(s) found at %q", c.Path)
+		}
 	}
 
 	var out = &TestCasesMetrics{
-		DesiredTestCases: map[string]bool{},
-		ActualTestCases:  map[string]bool{},
-	}
-
-	registerActualTestCaseNamesFn := func(lineContent string) {
-		log.V(6).Info("Actual testcase", "Received", lineContent)
-		lineContent = strings.TrimSpace(lineContent)
-		if strings.HasPrefix(lineContent, ActualTestCaseNamePrefix) {
-			tcid := strings.TrimSuffix(lineContent, ":")
-			log.V(3).Info("Registering actual tcid", "name", tcid)
-			out.ActualTestCases[tcid] = true
-		} else if strings.HasPrefix(lineContent, DeprecatedTestCaseIDPrefix) {
-			tcid := strings.TrimSuffix(lineContent, ":")
-			log.V(3).Info("Registering deprecated tcid", "name", tcid)
-			out.DeprecatedTestCases = append(
-				out.DeprecatedTestCases,
-				tcid,
-			)
-		}
+		DesiredTestCases:    map[string]PlannedTest{},
+		ActualTestCases:     map[string]ActualTestCase{},
+		DeprecatedTestCases: map[string]ActualTestCase{},
 	}
-	registerDesiredTestCaseNamesFn := func(lineContent string) {
-		log.V(6).Info("Desired testcase", "Received", lineContent)
-		lineContent = strings.TrimSpace(lineContent)
-		if strings.HasPrefix(lineContent, DesiredTestCaseNamePrefix) {
-			words := strings.Split(lineContent, DesiredTestCaseNameDelimiter)
-			if len(words) == 2 {
-				tcid := strings.TrimSpace(words[1])
-				log.V(3).Info("Registering desired tcid", "name", tcid)
-				out.DesiredTestCases[tcid] = true
-			}
-		}
+	files, includeFiles, commit, err := c.defaultFiles()
+	if err != nil {
+		return nil, err
 	}
-	// we support e2e metrics yaml files only
-	getRegisterTestCaseNamesFuncForFileName :=
-		func(filename string) func(string) {
-			if filename == c.ActualTestCasesFileName {
-				return registerActualTestCaseNamesFn
-			} else if filename == c.DesiredTestCasesFileName {
-				return registerDesiredTestCaseNamesFn
-			}
-			return nil
+	out.Commit = commit
+
+	// test cases that are declared more than once
+	var duplicates = map[string]bool{}
+
+	// there can be multiple sources for desired as well as actual
+	// test cases
+	for _, conf := range c.DesiredSources {
+		conf = withDefaultFiles(conf, files, includeFiles)
+		reads := c.countReads(&conf)
+		source, err := NewDesiredSource(conf)
+		if err != nil {
+			return nil, err
 		}
-	// there will be multiple config files i.e. desired & actual test
-	// case files in one location
-	for _, file := range files {
-		fileName := file.Name()
-		if file.IsDir() || file.Mode().IsDir() {
-			log.V(4).Info(
-				"Will skip config: Not a file",
-				"file", fileName,
-				"path", c.Path,
-			)
-			// we don't load folder(s)
+		log.V(2).Info("Will load desired source", "source", conf)
+		start := time.Now()
+		tests, err := source.LoadDesired()
+		if err != nil && conf.Optional && os.IsNotExist(errors.Cause(err)) {
+			log.V(4).Info("Will skip desired source: Not found", "source", conf)
 			continue
 		}
-		if !strings.HasSuffix(fileName, ".yaml") &&
-			!strings.HasSuffix(fileName, ".yml") {
-			log.V(4).Info(
-				"Will skip config: Not a yaml file",
-				"file", fileName,
-				"path", c.Path,
-			)
-			// we support only yaml files
-			continue
+		c.observeLoad(conf, start, reads, err)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to load %q", conf)
 		}
-		// get registry logic that registers all the test cases
-		// found in this file based on the name of this file
-		registerTestCaseNames := getRegisterTestCaseNamesFuncForFileName(fileName)
-		if registerTestCaseNames == nil {
-			log.V(4).Info(
-				"Will skip config",
-				"got-file", fileName,
-				"path", c.Path,
-				"want-file", c.DesiredTestCasesFileName, c.ActualTestCasesFileName,
-			)
+		for _, test := range tests {
+			log.V(3).Info("Registering desired tcid", "name", test.TCID)
+			if _, found := out.DesiredTestCases[test.TCID]; found {
+				duplicates[test.TCID] = true
+			}
+			out.DesiredTestCases[test.TCID] = test
+		}
+	}
+	for _, conf := range c.ActualSources {
+		conf = withDefaultFiles(conf, files, includeFiles)
+		reads := c.countReads(&conf)
+		source, err := NewActualSource(conf)
+		if err != nil {
+			return nil, err
+		}
+		log.V(2).Info("Will load actual source", "source", conf)
+		start := time.Now()
+		tests, err := source.LoadActual()
+		if err != nil && conf.Optional && os.IsNotExist(errors.Cause(err)) {
+			log.V(4).Info("Will skip actual source: Not found", "source", conf)
 			continue
 		}
-		// build full file path
-		fileNameWithPath := c.Path + fileName
-		log.V(2).Info("Will load config", "file", fileNameWithPath)
-
-		// logic that parses the file & registers the test case names
-		// found in this file
-		err := parseFileByLine(fileNameWithPath, registerTestCaseNames)
+		c.observeLoad(conf, start, reads, err)
 		if err != nil {
-			return nil, errors.Wrapf(
-				err,
-				"Failed to parse %q",
-				fileNameWithPath,
-			)
+			return nil, errors.Wrapf(err, "Failed to load %q", conf)
 		}
+		for _, test := range tests {
+			if test.ImplementationType == "" {
+				test.ImplementationType = prom.TestImplementationTypeLitmus
+			}
+			if test.Deprecated {
+				log.V(3).Info("Registering deprecated tcid", "name", test.TCID)
+				out.DeprecatedTestCases[test.TCID] = test
+				continue
+			}
+			log.V(3).Info("Registering actual tcid", "name", test.TCID)
+			if existing, found := out.ActualTestCases[test.TCID]; found &&
+				existing.unit() != test.unit() {
+				duplicates[test.TCID] = true
+			}
+			out.ActualTestCases[test.TCID] = test
+		}
+	}
+	for tcid := range duplicates {
+		out.DuplicateTestCases = append(out.DuplicateTestCases, tcid)
+	}
+	sort.Strings(out.DuplicateTestCases)
+	out.UndeclaredValues = c.Taxonomy.UndeclaredValues(out)
+	for _, undeclared := range out.UndeclaredValues {
+		log.V(2).Info("Found undeclared value", "value", undeclared.String())
 	}
 	log.V(4).Info("Config(s) loaded successfully", "path", c.Path)
 
 	actualTestCaseCount := len(out.ActualTestCases)
 	desiredTestCaseCount := len(out.DesiredTestCases)
-	c.prom.SetActualTestCount(&prom.ActualTestCount{
-		BaseTestCount: prom.BaseTestCount{
-			Value:                  float64(actualTestCaseCount),
-			TestImplementationType: prom.TestImplementationTypeLitmus,
-		},
-	})
-	c.prom.SetPlannedTestCount(&prom.PlannedTestCount{
-		BaseTestCount: prom.BaseTestCount{
-			Value:                  float64(desiredTestCaseCount),
-			TestImplementationType: prom.TestImplementationTypeLitmus,
-		},
-	})
+	c.prom.SetPlannedTestCounts(c.PipelineCoverage, c.plannedTestCounts(out))
+	c.prom.SetActualTestCounts(c.PipelineCoverage, c.actualTestCounts(out))
 	log.V(4).Info(
 		"Prometheus metrics were set",
 		"actual-test-count", actualTestCaseCount,
@@ -256,54 +438,184 @@ func (c *Loadable) Load() (*TestCasesMetrics, error) {
 	return out, nil
 }
 
-// processFileByLine parses the given file using
-// the provided parse logic
-func parseFileByLine(filename string, process func(string)) (err error) {
-	klog.V(3).Infof("Will parse file %q", filename)
-	file, err := os.Open(filename)
-	defer file.Close()
+// countReads wraps the readers of the given source to count the
+// files & bytes read by this source
+func (c *Loadable) countReads(conf *SourceConfig) *sourceReads {
+	var reads = &sourceReads{}
+	files := &countingReader{
+		FileReader: conf.Files,
+		reads:      reads,
+		counted:    map[string]bool{},
+	}
+	switch conf.IncludeFiles {
+	case nil:
+	case conf.Files:
+		// files that are read as includes are counted once
+		conf.IncludeFiles = files
+	default:
+		conf.IncludeFiles = &countingReader{
+			FileReader: conf.IncludeFiles,
+			reads:      reads,
+			counted:    map[string]bool{},
+		}
+	}
+	conf.Files = files
+	return reads
+}
+
+// observeLoad sets the metrics of the given source load that started
+// at the given time
+//
+// NOTE:
+//	Optional sources that are not found are skipped before this.
+// Optional sources that load without reading any file e.g. a glob
+// that matches nothing are skipped as well.
+func (c *Loadable) observeLoad(conf SourceConfig, start time.Time, reads *sourceReads, err error) {
+	if err == nil && conf.Optional && reads.files == 0 {
+		c.log.V(4).Info("Will skip source metrics: No files read", "source", conf)
+		return
+	}
+	end := time.Now()
+	duration := end.Sub(start).Seconds()
+	status := prom.SourceLoadStatusPassed
 	if err != nil {
-		return err
-	}
-
-	// Start reading from the file with a reader.
-	reader := bufio.NewReader(file)
-	// loop over all lines in the file
-	for {
-		var linecontent bytes.Buffer
-		var chunk []byte
-		var isPrefix bool
-		// loop over the buffer chunks inside one line
-		for {
-			chunk, isPrefix, err = reader.ReadLine()
-			linecontent.Write(chunk)
-			// If we've reached the end of the line
-			if !isPrefix {
-				// stop reading this line further
-				break
-			}
-			// If we're just at the EOF, break
-			if err != nil {
-				break
+		status = prom.SourceLoadStatusFailed
+	}
+	c.prom.ObserveSourceLoad(&prom.SourceLoad{
+		Namespace:         c.Namespace,
+		PipelineCoverage:  c.PipelineCoverage,
+		Kind:              conf.Format,
+		File:              conf.Path,
+		Status:            status,
+		DurationInSeconds: duration,
+		ParsedBytes:       float64(reads.bytes),
+		Time:              end,
+	})
+	switch conf.Format {
+	case FormatMasterPlan:
+		c.prom.ObserveMasterPlanYmlLoadDuration(&prom.MasterPlanYmlLoadDuration{
+			ValueInSeconds: duration,
+			Status:         prom.MasterPlanYmlLoadDurationStatus(status),
+		})
+	case FormatGitlabCI:
+		c.prom.ObserveGitlabCIYmlLoadDuration(&prom.GitlabCIYmlLoadDuration{
+			ValueInSeconds: duration,
+			Status:         prom.GitlabCIYmlLoadDurationStatus(status),
+		})
+	}
+}
+
+// plannedImplementationType returns the implementation type of the
+// planned test cases that are not implemented
+//
+// NOTE:
+//	This is the implementation type of the loaded actual test cases
+// if all of them have the same type. It defaults to litmus
+// otherwise.
+func plannedImplementationType(tcm *TestCasesMetrics) prom.TestImplementationType {
+	var implType prom.TestImplementationType
+	for _, tests := range []map[string]ActualTestCase{
+		tcm.ActualTestCases,
+		tcm.DeprecatedTestCases,
+	} {
+		for _, test := range tests {
+			if implType != "" && implType != test.ImplementationType {
+				return prom.TestImplementationTypeLitmus
 			}
+			implType = test.ImplementationType
 		}
-		// if we have completed reading the entire file then stop
-		if err == io.EOF {
-			break
+	}
+	if implType == "" {
+		return prom.TestImplementationTypeLitmus
+	}
+	return implType
+}
+
+// plannedTestCounts returns the count of planned test cases per
+// combination of dimensions
+//
+// NOTE:
+//	Implementation type of a planned test case is that of its
+// implemented test case.
+func (c *Loadable) plannedTestCounts(tcm *TestCasesMetrics) []prom.PlannedTestCount {
+	var counts = map[string]*prom.PlannedTestCount{}
+	defaultImplType := plannedImplementationType(tcm)
+	for _, test := range tcm.DesiredTestCases {
+		implType := defaultImplType
+		if actual, found := tcm.ActualTestCases[test.TCID]; found {
+			implType = actual.ImplementationType
+		}
+		dimensions := c.Taxonomy.DimensionValues(test)
+		key := c.Taxonomy.countKey(dimensions, implType)
+		if counts[key] == nil {
+			counts[key] = &prom.PlannedTestCount{
+				BaseTestCount: prom.BaseTestCount{
+					Dimensions:             dimensions,
+					TestImplementationType: implType,
+				},
+			}
 		}
-		if err == nil {
-			// at this point, the entire line's content will be available
-			line := linecontent.String()
-			// do not process a blank line
-			if line != "" {
-				// process the line in the provided callback fn
-				process(line)
+		counts[key].Value++
+	}
+	var out []prom.PlannedTestCount
+	for _, count := range counts {
+		out = append(out, *count)
+	}
+	return out
+}
+
+// actualTestCounts returns the count of implemented test cases per
+// combination of dimensions
+//
+// NOTE:
+//	Dimensions of an implemented test case are those of its planned
+// test case. These are empty if the test case is not planned.
+func (c *Loadable) actualTestCounts(tcm *TestCasesMetrics) []prom.ActualTestCount {
+	var counts = map[string]*prom.ActualTestCount{}
+	for _, test := range tcm.ActualTestCases {
+		// planned test is empty if not found
+		dimensions := c.Taxonomy.DimensionValues(tcm.DesiredTestCases[test.TCID])
+		key := c.Taxonomy.countKey(dimensions, test.ImplementationType)
+		if counts[key] == nil {
+			counts[key] = &prom.ActualTestCount{
+				BaseTestCount: prom.BaseTestCount{
+					Dimensions:             dimensions,
+					TestImplementationType: test.ImplementationType,
+				},
 			}
 		}
+		counts[key].Value++
 	}
-	if err != io.EOF {
-		return err
+	var out []prom.ActualTestCount
+	for _, count := range counts {
+		out = append(out, *count)
 	}
-	klog.V(4).Infof("Parsed file %q successfully", filename)
-	return nil
+	return out
+}
+
+// newActualTestCase returns the actual test case if the given name
+// has the test case id prefix or the deprecated test case id prefix
+func newActualTestCase(name string) (ActualTestCase, bool) {
+	if strings.HasPrefix(name, ActualTestCaseNamePrefix) {
+		return ActualTestCase{TCID: name}, true
+	}
+	if strings.HasPrefix(name, DeprecatedTestCaseIDPrefix) {
+		return ActualTestCase{TCID: name, Deprecated: true}, true
+	}
+	return ActualTestCase{}, false
+}
+
+// testCaseIDRegex matches the test case ids that are embedded in
+// free text e.g. descriptions & comments
+//
+// NOTE:
+//	Test case id can not end with the delimiter
+var testCaseIDRegex = regexp.MustCompile(
+	`\b(?:` + ActualTestCaseNamePrefix + `|` + DeprecatedTestCaseIDPrefix + `)` +
+		`[A-Za-z0-9_-]*[A-Za-z0-9_]`,
+)
+
+// findTestCaseIDs returns the test case ids found in the given text
+func findTestCaseIDs(text string) []string {
+	return testCaseIDRegex.FindAllString(text, -1)
 }
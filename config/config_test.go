@@ -17,8 +17,12 @@ limitations under the License.
 package config
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
 	"mayadata.io/e2e-metrics/metrics"
 	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
 )
@@ -61,7 +65,7 @@ func TestConfigLoad(t *testing.T) {
 		)
 	}
 	for _, eDesiredTestName := range expectDesiredTestNames {
-		if !metrics.DesiredTestCases[eDesiredTestName] {
+		if _, found := metrics.DesiredTestCases[eDesiredTestName]; !found {
 			t.Fatalf("Expected desired test name %q got %#v",
 				eDesiredTestName,
 				metrics.DesiredTestCases,
@@ -78,7 +82,7 @@ func TestConfigLoad(t *testing.T) {
 		)
 	}
 	for _, eActualTestName := range expectActualTestNames {
-		if !metrics.ActualTestCases[eActualTestName] {
+		if _, found := metrics.ActualTestCases[eActualTestName]; !found {
 			t.Fatalf("Expected actual test name %q got %#v",
 				eActualTestName,
 				metrics.ActualTestCases,
@@ -96,7 +100,7 @@ func TestConfigLoad(t *testing.T) {
 	}
 	for _, eDeprecatedTestName := range expectDeprecatedTestNames {
 		var found bool
-		for _, gotDeprecatedTestName := range metrics.DeprecatedTestCases {
+		for gotDeprecatedTestName := range metrics.DeprecatedTestCases {
 			if eDeprecatedTestName == gotDeprecatedTestName {
 				found = true
 			}
@@ -109,3 +113,231 @@ func TestConfigLoad(t *testing.T) {
 		}
 	}
 }
+
+func TestConfigLoadTestCountMetrics(t *testing.T) {
+	var tests = map[string]struct {
+		taxonomy      *Taxonomy
+		actualSources []SourceConfig
+		expectPlanned string
+		expectActual  string
+	}{
+		"default taxonomy": {
+			expectPlanned: `
+# HELP e2emet_planned_test_count Total number of planned test cases.
+# TYPE e2emet_planned_test_count gauge
+e2emet_planned_test_count{component="",feature="",kind="",pipelinecoverage="gcp",testimpltype="litmus"} 1
+e2emet_planned_test_count{component="director",feature="auth",kind="googleauth",pipelinecoverage="gcp",testimpltype="litmus"} 1
+e2emet_planned_test_count{component="director",feature="auth",kind="localauth",pipelinecoverage="gcp",testimpltype="litmus"} 1
+e2emet_planned_test_count{component="director",feature="dmaas",kind="backup",pipelinecoverage="gcp",testimpltype="litmus"} 1
+e2emet_planned_test_count{component="director",feature="dmaas",kind="restore",pipelinecoverage="gcp",testimpltype="litmus"} 1
+`,
+			expectActual: `
+# HELP e2emet_actual_test_count Total number of actual test cases.
+# TYPE e2emet_actual_test_count gauge
+e2emet_actual_test_count{component="",feature="",kind="",pipelinecoverage="gcp",testimpltype="litmus"} 1
+e2emet_actual_test_count{component="director",feature="auth",kind="googleauth",pipelinecoverage="gcp",testimpltype="litmus"} 1
+e2emet_actual_test_count{component="director",feature="auth",kind="localauth",pipelinecoverage="gcp",testimpltype="litmus"} 1
+e2emet_actual_test_count{component="director",feature="dmaas",kind="backup",pipelinecoverage="gcp",testimpltype="litmus"} 1
+`,
+		},
+		"custom taxonomy": {
+			taxonomy: &Taxonomy{
+				Dimensions: []Dimension{
+					{Name: "team", LabelKey: "team"},
+				},
+			},
+			expectPlanned: `
+# HELP e2emet_planned_test_count Total number of planned test cases.
+# TYPE e2emet_planned_test_count gauge
+e2emet_planned_test_count{pipelinecoverage="gcp",team="",testimpltype="litmus"} 1
+e2emet_planned_test_count{pipelinecoverage="gcp",team="auth",testimpltype="litmus"} 2
+e2emet_planned_test_count{pipelinecoverage="gcp",team="dmaas",testimpltype="litmus"} 2
+`,
+			expectActual: `
+# HELP e2emet_actual_test_count Total number of actual test cases.
+# TYPE e2emet_actual_test_count gauge
+e2emet_actual_test_count{pipelinecoverage="gcp",team="",testimpltype="litmus"} 1
+e2emet_actual_test_count{pipelinecoverage="gcp",team="auth",testimpltype="litmus"} 2
+e2emet_actual_test_count{pipelinecoverage="gcp",team="dmaas",testimpltype="litmus"} 1
+`,
+		},
+		"go tests": {
+			actualSources: []SourceConfig{
+				{
+					Format: FormatGoSource,
+					Path:   ".",
+					Files: NewMapReader(map[string]string{
+						"e2e/backup_test.go": `package e2e
+
+import "testing"
+
+// TestBackup verifies TCID-DMAAS-BACKUP
+func TestBackup(t *testing.T) {}
+`,
+					}),
+				},
+			},
+			expectPlanned: `
+# HELP e2emet_planned_test_count Total number of planned test cases.
+# TYPE e2emet_planned_test_count gauge
+e2emet_planned_test_count{component="",feature="",kind="",pipelinecoverage="gcp",testimpltype="dope"} 1
+e2emet_planned_test_count{component="director",feature="auth",kind="googleauth",pipelinecoverage="gcp",testimpltype="dope"} 1
+e2emet_planned_test_count{component="director",feature="auth",kind="localauth",pipelinecoverage="gcp",testimpltype="dope"} 1
+e2emet_planned_test_count{component="director",feature="dmaas",kind="backup",pipelinecoverage="gcp",testimpltype="dope"} 1
+e2emet_planned_test_count{component="director",feature="dmaas",kind="restore",pipelinecoverage="gcp",testimpltype="dope"} 1
+`,
+			expectActual: `
+# HELP e2emet_actual_test_count Total number of actual test cases.
+# TYPE e2emet_actual_test_count gauge
+e2emet_actual_test_count{component="director",feature="dmaas",kind="backup",pipelinecoverage="gcp",testimpltype="dope"} 1
+`,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			log := &logstesting.TestLogger{T: t}
+			taxonomy := mock.taxonomy
+			if taxonomy == nil {
+				taxonomy = DefaultTaxonomy()
+			}
+			prom := metrics.NewFromConfig(metrics.Config{
+				Log:                 log,
+				TestCountDimensions: taxonomy.DimensionNames(),
+			})
+			config := New(LoadableConfig{
+				Path:          "testdata/labels",
+				Log:           log,
+				Prom:          prom,
+				Taxonomy:      taxonomy,
+				ActualSources: mock.actualSources,
+
+				PipelineCoverage: "gcp",
+			})
+			_, err := config.Load()
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			err = testutil.CollectAndCompare(
+				prom.PlannedTestsTotal, strings.NewReader(mock.expectPlanned),
+			)
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			err = testutil.CollectAndCompare(
+				prom.ActualTestsTotal, strings.NewReader(mock.expectActual),
+			)
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigLoadSourceMetrics(t *testing.T) {
+	log := &logstesting.TestLogger{T: t}
+	prom := metrics.New(log)
+	config := New(LoadableConfig{
+		Path: "testdata/labels",
+		Log:  log,
+		Prom: prom,
+		DesiredSources: []SourceConfig{
+			{Format: FormatMasterPlan, Path: ".master-plan.yml"},
+		},
+		PipelineCoverage: "gcp",
+		Namespace:        "e2e-metrics",
+		ActualSources: []SourceConfig{
+			{Format: FormatGitlabCI, Path: ".gitlab-ci.yml"},
+		},
+	})
+	_, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	// gitlab ci file is not a master plan
+	invalid := New(LoadableConfig{
+		Path: "testdata/labels",
+		Log:  log,
+		Prom: prom,
+		DesiredSources: []SourceConfig{
+			{Format: FormatMasterPlan, Path: ".gitlab-ci.yml"},
+		},
+		PipelineCoverage: "aws",
+		Namespace:        "e2e-metrics",
+	})
+	_, err = invalid.Load()
+	if err == nil {
+		t.Fatalf("Expected error got none")
+	}
+
+	err = testutil.CollectAndCompare(prom.SourceParsedBytesTotal, strings.NewReader(`
+# HELP e2emet_source_parsed_bytes_total Total number of bytes read while parsing a test source.
+# TYPE e2emet_source_parsed_bytes_total counter
+e2emet_source_parsed_bytes_total{file=".gitlab-ci.yml",kind="gitlabci",namespace="e2e-metrics",pipelinecoverage="gcp"} 241
+e2emet_source_parsed_bytes_total{file=".gitlab-ci.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="aws"} 241
+e2emet_source_parsed_bytes_total{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp"} 839
+`))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	for _, labels := range [][]string{
+		{"e2e-metrics", "gcp", FormatMasterPlan, ".master-plan.yml"},
+		{"e2e-metrics", "gcp", FormatGitlabCI, ".gitlab-ci.yml"},
+	} {
+		got := testutil.ToFloat64(prom.SourceLastSuccessfulLoadTimestamp.WithLabelValues(labels...))
+		if got <= 0 {
+			t.Fatalf("Expected last successful load of %v got %v", labels, got)
+		}
+	}
+	// failed load does not set the last successful load
+	found := prom.SourceLastSuccessfulLoadTimestamp.DeleteLabelValues(
+		"e2e-metrics", "aws", FormatMasterPlan, ".gitlab-ci.yml",
+	)
+	if found {
+		t.Fatalf("Expected no last successful load of failed source")
+	}
+}
+
+func TestConfigLoadSourceMetricsReadOnce(t *testing.T) {
+	const ci = `
+include:
+- local: jobs.yml
+`
+	const jobs = `
+TCID-OPENEBS-UPGRADE:
+  script:
+  - ./upgrade
+`
+	files := NewMapReader(map[string]string{
+		".gitlab-ci.yml": ci,
+		"jobs.yml":       jobs,
+	})
+	log := &logstesting.TestLogger{T: t}
+	prom := metrics.New(log)
+	config := New(LoadableConfig{
+		Log:  log,
+		Prom: prom,
+		DesiredSources: []SourceConfig{
+			{Format: FormatMasterPlan, Path: "missing.yml", Files: files, Optional: true},
+		},
+		ActualSources: []SourceConfig{
+			{Format: FormatGitlabCI, Path: ".gitlab-ci.yml", Files: files, IncludeFiles: files},
+			{Format: FormatGithubActions, Path: ".github/workflows/*.yml", Files: files, Optional: true},
+		},
+	})
+	_, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	// included file is counted once & optional sources without any
+	// file are not observed
+	err = testutil.CollectAndCompare(prom.SourceParsedBytesTotal, strings.NewReader(fmt.Sprintf(`
+# HELP e2emet_source_parsed_bytes_total Total number of bytes read while parsing a test source.
+# TYPE e2emet_source_parsed_bytes_total counter
+e2emet_source_parsed_bytes_total{file=".gitlab-ci.yml",kind="gitlabci",namespace="",pipelinecoverage=""} %d
+`, len(ci)+len(jobs))))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FileReader provides access to the files that test sources are
+// loaded from
+//
+// NOTE:
+//	File names are slash separated & are relative to the root of
+// the reader
+type FileReader interface {
+	// ReadFile returns the content of the given file
+	ReadFile(name string) ([]byte, error)
+
+	// ListFiles returns the names of all the files found in the
+	// given directory & its sub directories
+	ListFiles(dir string) ([]string, error)
+}
+
+// DirReader reads files from a directory of the local file system
+type DirReader struct {
+	Root string
+}
+
+// NewDirReader returns a new instance of DirReader
+func NewDirReader(root string) *DirReader {
+	return &DirReader{
+		Root: root,
+	}
+}
+
+// path returns the local file system path of the given file name
+//
+// NOTE:
+//	A reader without any root reads any file of the local file
+// system. Otherwise, the given name must be within the root.
+func (r *DirReader) path(op, name string) (string, error) {
+	local := filepath.FromSlash(name)
+	if r.Root == "" {
+		return local, nil
+	}
+	if isOutsideRoot(name) {
+		return "", &os.PathError{Op: op, Path: name, Err: errOutsideRoot}
+	}
+	return filepath.Join(r.Root, local), nil
+}
+
+// ReadFile implements FileReader interface
+func (r *DirReader) ReadFile(name string) ([]byte, error) {
+	file, err := r.path("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(file)
+}
+
+// ListFiles implements FileReader interface
+func (r *DirReader) ListFiles(dir string) ([]string, error) {
+	base, err := r.path("lstat", dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	err = filepath.Walk(base, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(base, file)
+		if err != nil {
+			return err
+		}
+		names = append(names, path.Join(dir, filepath.ToSlash(rel)))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// MapReader reads files from memory e.g. the data of a ConfigMap
+//
+// NOTE:
+//	File names are the keys of the map. Hence, the names are
+// expected to be slash separated.
+type MapReader struct {
+	Files map[string]string
+}
+
+// NewMapReader returns a new instance of MapReader
+func NewMapReader(files map[string]string) *MapReader {
+	return &MapReader{
+		Files: files,
+	}
+}
+
+// ReadFile implements FileReader interface
+func (r *MapReader) ReadFile(name string) ([]byte, error) {
+	data, found := r.Files[path.Clean(name)]
+	if !found {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return []byte(data), nil
+}
+
+// ListFiles implements FileReader interface
+func (r *MapReader) ListFiles(dir string) ([]string, error) {
+	dir = path.Clean(dir)
+	var names []string
+	for name := range r.Files {
+		if dir == "." || strings.HasPrefix(name, dir+"/") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, &os.PathError{Op: "lstat", Path: dir, Err: os.ErrNotExist}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// sourceReads has the files read by a source & its includes
+type sourceReads struct {
+	bytes int
+	files int
+}
+
+// countingReader counts the bytes of all the files read via the
+// wrapped reader
+//
+// NOTE:
+//	A file is counted once even if it is read more than once e.g.
+// a local include that is probed before it is parsed
+type countingReader struct {
+	FileReader
+
+	// reads is shared by the readers of a source & its includes
+	reads *sourceReads
+
+	// names of the files that are counted
+	counted map[string]bool
+}
+
+// ReadFile implements FileReader interface
+func (r *countingReader) ReadFile(name string) ([]byte, error) {
+	data, err := r.FileReader.ReadFile(name)
+	if err != nil || r.counted[path.Clean(name)] {
+		return data, err
+	}
+	r.counted[path.Clean(name)] = true
+	r.reads.bytes += len(data)
+	r.reads.files++
+	return data, nil
+}
+
+// errOutsideRoot is returned when a file outside the root of a
+// reader is read
+var errOutsideRoot = errors.New("path is outside the root directory")
+
+// isOutsideRoot returns true if the given slash separated name is
+// absolute or refers to a parent of the root
+func isOutsideRoot(name string) bool {
+	if path.IsAbs(name) || filepath.IsAbs(filepath.FromSlash(name)) {
+		return true
+	}
+	clean := path.Clean(filepath.ToSlash(name))
+	return clean == ".." || strings.HasPrefix(clean, "../")
+}
+
+// skippedDirs are the directories that are never listed while
+// looking up the files of a source
+var skippedDirs = map[string]bool{
+	"vendor":   true,
+	"testdata": true,
+	".git":     true,
+}
+
+// isSkippedFile returns true if the given file is within any of
+// the skipped directories
+func isSkippedFile(name string) bool {
+	for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if skippedDirs[path.Base(dir)] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnySuffix returns true if the given name ends with any of the
+// given suffixes
+func hasAnySuffix(name string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGlob returns true if the given name is a glob pattern
+func isGlob(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// globFiles returns the sorted names of the files that match the
+// given pattern
+//
+// NOTE:
+//	Pattern follows the syntax of path.Match & hence '*' does not
+// match the '/' separator
+func globFiles(files FileReader, pattern string) ([]string, error) {
+	pattern = path.Clean(strings.TrimPrefix(pattern, "./"))
+	// list files from the longest directory without any wildcard
+	dir := pattern
+	for isGlob(dir) {
+		dir = path.Dir(dir)
+	}
+	if dir == pattern {
+		dir = path.Dir(pattern)
+	}
+	names, err := files.ListFiles(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var matches []string
+	for _, name := range names {
+		matched, err := path.Match(pattern, path.Clean(name))
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+// DefaultGitRef is the ref that files are read from if no ref is
+// set
+const DefaultGitRef string = "HEAD"
+
+// GitReader reads files from a commit of a local git repository
+//
+// NOTE:
+//	Repository can either be bare or have a working tree. Files of
+// the working tree are never read. Hence, uncommitted changes are
+// not considered.
+type GitReader struct {
+	// Commit is the SHA of the commit that files are read from
+	Commit string
+
+	tree *object.Tree
+}
+
+// NewGitReader returns a new instance of GitReader that reads files
+// from the given ref of the given repository. Ref can be a branch,
+// a tag or a commit SHA.
+func NewGitReader(repoPath, ref string) (*GitReader, error) {
+	if ref == "" {
+		ref = DefaultGitRef
+	}
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to open git repository %q", repoPath)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to resolve %q of git repository %q", ref, repoPath)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get commit %q of git repository %q", hash, repoPath)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get tree of commit %q", hash)
+	}
+	return &GitReader{
+		Commit: hash.String(),
+		tree:   tree,
+	}, nil
+}
+
+// cleanGitPath returns the given name relative to the root of the
+// git tree
+func cleanGitPath(name string) string {
+	return path.Clean(strings.TrimPrefix(name, "/"))
+}
+
+// ReadFile implements FileReader interface
+func (r *GitReader) ReadFile(name string) ([]byte, error) {
+	file, err := r.tree.File(cleanGitPath(name))
+	if err == object.ErrFileNotFound {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read %q from commit %q", name, r.Commit)
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read %q from commit %q", name, r.Commit)
+	}
+	return []byte(content), nil
+}
+
+// ListFiles implements FileReader interface
+func (r *GitReader) ListFiles(dir string) ([]string, error) {
+	dir = cleanGitPath(dir)
+	tree := r.tree
+	if dir != "." {
+		var err error
+		tree, err = r.tree.Tree(dir)
+		if err == object.ErrDirectoryNotFound {
+			return nil, &os.PathError{Op: "lstat", Path: dir, Err: os.ErrNotExist}
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to list %q from commit %q", dir, r.Commit)
+		}
+	}
+	var names []string
+	err := tree.Files().ForEach(func(file *object.File) error {
+		names = append(names, path.Join(dir, file.Name))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list %q from commit %q", dir, r.Commit)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"mayadata.io/e2e-metrics/metrics"
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+)
+
+// gitCommit writes the given files to the working tree of the given
+// repository & commits them
+func gitCommit(t *testing.T, repo *git.Repository, dir string, files map[string]string) string {
+	tree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		err = os.MkdirAll(filepath.Dir(file), 0755)
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+		err = ioutil.WriteFile(file, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+		_, err = tree.Add(name)
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+	}
+	hash, err := tree.Commit("update tests", &git.CommitOptions{
+		Author: &object.Signature{Name: "e2e", Email: "e2e@mayadata.io", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	return hash.String()
+}
+
+func TestConfigLoadGit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "e2e-metrics-git")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	workDir := filepath.Join(dir, "work")
+	repo, err := git.PlainInit(workDir, false)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	v1 := gitCommit(t, repo, workDir, map[string]string{
+		".master-plan.yml": `
+kind: MasterPlan
+spec:
+  tests:
+  - tcid: TCID-OPENEBS-UPGRADE
+  - tcid: TCID-OPENEBS-BACKUP
+`,
+		".gitlab-ci.yml": `
+include:
+- local: ci/upgrade.yml
+`,
+		"ci/upgrade.yml": `
+TCID-OPENEBS-UPGRADE:
+  script:
+  - ./upgrade
+`,
+	})
+	_, err = repo.CreateTag("v1", plumbing.NewHash(v1), nil)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	v2 := gitCommit(t, repo, workDir, map[string]string{
+		".gitlab-ci.yml": `
+include:
+- local: ci/upgrade.yml
+- local: ci/backup.yml
+`,
+		"ci/backup.yml": `
+TCID-OPENEBS-BACKUP:
+  script:
+  - ./backup
+`,
+	})
+	// uncommitted changes are never read
+	err = ioutil.WriteFile(filepath.Join(workDir, "ci/upgrade.yml"), []byte("invalid"), 0644)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+
+	bareDir := filepath.Join(dir, "bare")
+	_, err = git.PlainClone(bareDir, true, &git.CloneOptions{URL: workDir})
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+
+	var tests = map[string]struct {
+		path         string
+		ref          string
+		expectActual []string
+		expectCommit string
+		isErr        bool
+	}{
+		"tag": {
+			path:         workDir,
+			ref:          "v1",
+			expectActual: []string{"TCID-OPENEBS-UPGRADE"},
+			expectCommit: v1,
+		},
+		"commit sha": {
+			path:         workDir,
+			ref:          v1,
+			expectActual: []string{"TCID-OPENEBS-UPGRADE"},
+			expectCommit: v1,
+		},
+		"head": {
+			path:         workDir,
+			ref:          "HEAD",
+			expectActual: []string{"TCID-OPENEBS-BACKUP", "TCID-OPENEBS-UPGRADE"},
+			expectCommit: v2,
+		},
+		"branch of bare repository": {
+			path:         bareDir,
+			ref:          "master",
+			expectActual: []string{"TCID-OPENEBS-BACKUP", "TCID-OPENEBS-UPGRADE"},
+			expectCommit: v2,
+		},
+		"missing ref": {
+			path:  workDir,
+			ref:   "v2",
+			isErr: true,
+		},
+		"missing repository": {
+			path:  filepath.Join(dir, "missing"),
+			ref:   "HEAD",
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			log := &logstesting.TestLogger{
+				T: t,
+			}
+			config := New(LoadableConfig{
+				Path:   mock.path,
+				GitRef: mock.ref,
+				Log:    log,
+				Prom:   metrics.New(log),
+			})
+			got, err := config.Load()
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			var gotActual []string
+			for tcid := range got.ActualTestCases {
+				gotActual = append(gotActual, tcid)
+			}
+			sort.Strings(gotActual)
+			if !reflect.DeepEqual(gotActual, mock.expectActual) {
+				t.Fatalf("Expected actual %v got %v", mock.expectActual, gotActual)
+			}
+			if len(got.DesiredTestCases) != 2 {
+				t.Fatalf("Expected 2 desired got %+v", got.DesiredTestCases)
+			}
+			if got.Commit != mock.expectCommit {
+				t.Fatalf("Expected commit %q got %q", mock.expectCommit, got.Commit)
+			}
+		})
+	}
+}
@@ -0,0 +1,435 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FormatGithubActions is the source format of github actions
+	// workflow files
+	FormatGithubActions string = "githubactions"
+)
+
+func init() {
+	RegisterActualSource(FormatGithubActions, NewGithubActionsSource)
+}
+
+// githubMatrixExprRegex matches the expressions that refer to a
+// matrix value e.g. '${{ matrix.platform }}'
+var githubMatrixExprRegex = regexp.MustCompile(
+	`\$\{\{\s*matrix\.([A-Za-z0-9_-]+)\s*\}\}`,
+)
+
+// GithubWorkflow is the typed representation of a github actions
+// workflow file
+//
+// NOTE:
+//	A sample workflow looks like below:
+//
+//	name: e2e
+//	on: [push]
+//	jobs:
+//	  TCID-DIR-HEALTH-CHECK:
+//	    runs-on: ubuntu-latest
+//	    steps:
+//	    - run: ./stages/3-director-sanity-check/maya-io-server-check
+//	  upgrade:
+//	    name: TCID-OPENEBS-UPGRADE-${{ matrix.engine }}
+//	    strategy:
+//	      matrix:
+//	        engine: [CSTOR, JIVA]
+type GithubWorkflow struct {
+	Name string `yaml:"name"`
+
+	// File is the workflow file
+	File string `yaml:"-"`
+
+	// Jobs has the jobs in their order of declaration
+	Jobs []*GithubWorkflowJob `yaml:"-"`
+}
+
+// GithubWorkflowJob is a job declared in a github actions workflow
+type GithubWorkflowJob struct {
+	// ID is the key that declares this job
+	ID string `yaml:"-"`
+
+	// File is the workflow file where this job is declared
+	File string `yaml:"-"`
+
+	// Line is the line where this job is declared
+	Line int `yaml:"-"`
+
+	// Name is the display name of this job. It may refer to the
+	// values of the matrix.
+	Name     string                 `yaml:"name"`
+	Needs    StringList             `yaml:"needs"`
+	If       string                 `yaml:"if"`
+	Strategy GithubWorkflowStrategy `yaml:"strategy"`
+	Steps    []GithubWorkflowStep   `yaml:"steps"`
+}
+
+// GithubWorkflowStep is one of the steps of a workflow job
+type GithubWorkflowStep struct {
+	ID   string `yaml:"id"`
+	Name string `yaml:"name"`
+	Uses string `yaml:"uses"`
+	Run  string `yaml:"run"`
+}
+
+// GithubWorkflowStrategy decides the variations a workflow job
+// runs with
+type GithubWorkflowStrategy struct {
+	Matrix GithubWorkflowMatrix `yaml:"matrix"`
+}
+
+// GithubWorkflowMatrix has the variables whose combinations result
+// in multiple runs of the same workflow job
+type GithubWorkflowMatrix struct {
+	// Keys has the matrix variables in their order of declaration
+	Keys []string
+
+	// Values has the values of every matrix variable
+	Values map[string][]interface{}
+
+	// Include has the combinations that are added to the matrix
+	Include []map[string]interface{}
+
+	// Exclude has the combinations that are removed from the matrix
+	Exclude []map[string]interface{}
+
+	// Expression is set if the matrix or any of its variables is
+	// an expression e.g. '${{ fromJson(needs.setup.outputs.matrix) }}'
+	// that can only be evaluated while the workflow runs
+	Expression string
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+//
+// NOTE:
+//	Matrix can either be an expression or a mapping of variables
+func (m *GithubWorkflowMatrix) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		m.Expression = node.Value
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		var values map[string]interface{}
+		// this returns a type error with line number
+		return node.Decode(&values)
+	}
+	m.Values = map[string][]interface{}{}
+	for _, pair := range mappingPairs(node) {
+		key, value := pair[0], pair[1]
+		var err error
+		switch {
+		case key.Value == "include":
+			err = decodeGithubMatrixEntries(value, &m.Include, &m.Expression)
+		case key.Value == "exclude":
+			err = decodeGithubMatrixEntries(value, &m.Exclude, &m.Expression)
+		case value.Kind == yaml.ScalarNode:
+			m.Expression = value.Value
+		default:
+			var values []interface{}
+			err = value.Decode(&values)
+			m.Keys = append(m.Keys, key.Value)
+			m.Values[key.Value] = values
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeGithubMatrixEntries decodes the include or exclude entries
+// of a matrix. The expression is set if these entries are not known
+// till the workflow runs.
+func decodeGithubMatrixEntries(
+	node *yaml.Node,
+	entries *[]map[string]interface{},
+	expression *string,
+) error {
+	if node.Kind == yaml.ScalarNode {
+		*expression = node.Value
+		return nil
+	}
+	return node.Decode(entries)
+}
+
+// Combinations returns the matrix combinations a job runs with.
+// A job without any matrix runs once with an empty combination.
+// False is returned if the combinations can not be evaluated
+// before the workflow runs.
+//
+// NOTE:
+//	Combinations are evaluated the same way as github i.e. all the
+// combinations of the variables are built, then the excluded ones
+// are removed & finally the included ones are either merged into
+// the matching combinations or added as new ones.
+func (m GithubWorkflowMatrix) Combinations() ([]map[string]interface{}, bool) {
+	if m.Expression != "" {
+		return nil, false
+	}
+	var combinations []map[string]interface{}
+	if len(m.Keys) != 0 || len(m.Include) == 0 {
+		combinations = []map[string]interface{}{{}}
+	}
+	for _, key := range m.Keys {
+		var product []map[string]interface{}
+		for _, combination := range combinations {
+			for _, value := range m.Values[key] {
+				next := copyGithubMatrixCombination(combination)
+				next[key] = value
+				product = append(product, next)
+			}
+		}
+		combinations = product
+	}
+	var filtered []map[string]interface{}
+	for _, combination := range combinations {
+		var excluded bool
+		for _, exclude := range m.Exclude {
+			if matchesGithubMatrixEntry(combination, exclude, nil) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			filtered = append(filtered, combination)
+		}
+	}
+	combinations = filtered
+	var original = map[string]bool{}
+	for _, key := range m.Keys {
+		original[key] = true
+	}
+	// includes are merged only into the combinations of the
+	// original variables & never into the ones added by includes
+	base := combinations
+	for _, include := range m.Include {
+		var merged bool
+		for _, combination := range base {
+			// an include can not overwrite the original values of
+			// a combination
+			if !matchesGithubMatrixEntry(combination, include, original) {
+				continue
+			}
+			for key, value := range include {
+				combination[key] = value
+			}
+			merged = true
+		}
+		if !merged {
+			combinations = append(combinations, copyGithubMatrixCombination(include))
+		}
+	}
+	return combinations, true
+}
+
+// matchesGithubMatrixEntry returns true if the given combination has
+// the same values as that of the given entry. Only the given keys
+// are compared if keys is not nil.
+func matchesGithubMatrixEntry(
+	combination map[string]interface{},
+	entry map[string]interface{},
+	keys map[string]bool,
+) bool {
+	for key, value := range entry {
+		if keys != nil && !keys[key] {
+			continue
+		}
+		got, found := combination[key]
+		if !found || toString(got) != toString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// copyGithubMatrixCombination returns a copy of the given
+// combination
+func copyGithubMatrixCombination(
+	combination map[string]interface{},
+) map[string]interface{} {
+	var out = make(map[string]interface{}, len(combination))
+	for key, value := range combination {
+		out[key] = value
+	}
+	return out
+}
+
+// GithubWorkflowRun is one of the runs of a workflow job
+type GithubWorkflowRun struct {
+	// Name is the job's name after substituting the matrix values.
+	// It is the job's id if the job has no name.
+	Name string
+
+	// Matrix is the matrix combination of this run
+	Matrix map[string]interface{}
+}
+
+// Runs returns the runs of this job i.e. one run per matrix
+// combination
+//
+// NOTE:
+//	A job whose matrix can not be evaluated before the workflow runs
+// is returned as a single run without any matrix values
+func (j *GithubWorkflowJob) Runs() []GithubWorkflowRun {
+	combinations, ok := j.Strategy.Matrix.Combinations()
+	if !ok {
+		combinations = []map[string]interface{}{{}}
+	}
+	var runs []GithubWorkflowRun
+	for _, combination := range combinations {
+		name := j.ID
+		if j.Name != "" {
+			name = expandGithubMatrixExpr(j.Name, combination)
+		}
+		runs = append(runs, GithubWorkflowRun{
+			Name:   name,
+			Matrix: combination,
+		})
+	}
+	return runs
+}
+
+// expandGithubMatrixExpr replaces the matrix expressions found in
+// the given value with the values of the given combination.
+// Expressions that refer to unknown variables are left as is.
+func expandGithubMatrixExpr(value string, combination map[string]interface{}) string {
+	return githubMatrixExprRegex.ReplaceAllStringFunc(value, func(expr string) string {
+		key := githubMatrixExprRegex.FindStringSubmatch(expr)[1]
+		got, found := combination[key]
+		if !found {
+			return expr
+		}
+		return toString(got)
+	})
+}
+
+// ParseGithubWorkflow decodes the given data into github actions
+// workflow
+func ParseGithubWorkflow(filename string, data []byte) (*GithubWorkflow, error) {
+	var doc yaml.Node
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, wrapYAMLError(filename, err)
+	}
+	var workflow = &GithubWorkflow{
+		File: filename,
+	}
+	root := documentRoot(&doc)
+	if root == nil || root.Kind == 0 {
+		// empty file is a valid workflow without jobs
+		return workflow, nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, newFileError(filename, root.Line, "expected a workflow mapping")
+	}
+	var errs FileErrors
+	err = root.Decode(workflow)
+	if err != nil {
+		errs = appendYAMLError(errs, filename, err)
+	}
+	jobs := mappingValue(root, "jobs")
+	if jobs != nil && jobs.Kind != yaml.MappingNode {
+		errs = append(errs, newFileError(filename, jobs.Line, "expected a mapping of jobs"))
+		jobs = nil
+	}
+	for _, pair := range mappingPairs(jobs) {
+		key, value := pair[0], pair[1]
+		var job = &GithubWorkflowJob{}
+		err = value.Decode(job)
+		if err != nil {
+			errs = appendYAMLError(errs, filename, err)
+			continue
+		}
+		job.ID = key.Value
+		job.File = filename
+		job.Line = key.Line
+		workflow.Jobs = append(workflow.Jobs, job)
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return workflow, nil
+}
+
+// GithubActionsSource loads the actual test cases from the jobs of
+// github actions workflows
+type GithubActionsSource struct {
+	SourceConfig
+}
+
+// NewGithubActionsSource returns a new instance of
+// GithubActionsSource
+func NewGithubActionsSource(conf SourceConfig) (ActualSource, error) {
+	return &GithubActionsSource{
+		SourceConfig: conf,
+	}, nil
+}
+
+// LoadActual implements ActualSource interface
+//
+// NOTE:
+//	Every run of a job is a test case if the run's name has the
+// test case id prefix. The job's id is used instead if the run's
+// name does not have this prefix. Path can be a glob pattern to
+// load multiple workflows.
+func (s *GithubActionsSource) LoadActual() ([]ActualTestCase, error) {
+	files, err := s.matchingFiles()
+	if err != nil {
+		return nil, err
+	}
+	var tests []ActualTestCase
+	for _, file := range files {
+		data, err := s.Files.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		workflow, err := ParseGithubWorkflow(file, data)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range workflow.Jobs {
+			// runs of a job that are not named by the matrix map
+			// to the same test case
+			var seen = map[string]bool{}
+			for _, run := range job.Runs() {
+				test, ok := newActualTestCase(run.Name)
+				if !ok {
+					test, ok = newActualTestCase(job.ID)
+				}
+				if !ok || seen[test.TCID] {
+					continue
+				}
+				seen[test.TCID] = true
+				test.Format = FormatGithubActions
+				test.File = job.File
+				test.Line = job.Line
+				test.WorkflowJob = job
+				test.Matrix = run.Matrix
+				tests = append(tests, test)
+			}
+		}
+	}
+	return tests, nil
+}
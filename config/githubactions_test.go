@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"mayadata.io/e2e-metrics/metrics"
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+)
+
+func TestParseGithubWorkflowRuns(t *testing.T) {
+	var tests = map[string]struct {
+		data       string
+		expectRuns map[string][]string
+		expectErr  string
+	}{
+		"job without matrix": {
+			data: `
+jobs:
+  TCID-101:
+    runs-on: ubuntu-latest
+`,
+			expectRuns: map[string][]string{
+				"TCID-101": {"TCID-101"},
+			},
+		},
+		"matrix with exclude": {
+			data: `
+jobs:
+  e2e:
+    name: TCID-${{ matrix.os }}-${{matrix.k8s}}
+    strategy:
+      matrix:
+        os: [ubuntu, centos]
+        k8s: [1.17, 1.18]
+        exclude:
+        - os: centos
+          k8s: 1.17
+`,
+			expectRuns: map[string][]string{
+				"e2e": {
+					"TCID-ubuntu-1.17",
+					"TCID-ubuntu-1.18",
+					"TCID-centos-1.18",
+				},
+			},
+		},
+		"matrix with include": {
+			data: `
+jobs:
+  e2e:
+    name: TCID-${{ matrix.os }}-${{ matrix.suffix }}
+    strategy:
+      matrix:
+        os: [ubuntu, centos]
+        include:
+        - os: centos
+          suffix: v2
+        - os: windows
+          suffix: v1
+`,
+			expectRuns: map[string][]string{
+				"e2e": {
+					"TCID-ubuntu-${{ matrix.suffix }}",
+					"TCID-centos-v2",
+					"TCID-windows-v1",
+				},
+			},
+		},
+		"matrix with only include": {
+			data: `
+jobs:
+  e2e:
+    name: ${{ matrix.tcid }}
+    strategy:
+      matrix:
+        include:
+        - tcid: TCID-101
+        - tcid: TCID-201
+`,
+			expectRuns: map[string][]string{
+				"e2e": {"TCID-101", "TCID-201"},
+			},
+		},
+		"matrix as expression": {
+			data: `
+jobs:
+  TCID-101:
+    name: TCID-101-${{ matrix.os }}
+    strategy:
+      matrix: ${{ fromJson(needs.setup.outputs.matrix) }}
+`,
+			expectRuns: map[string][]string{
+				"TCID-101": {"TCID-101-${{ matrix.os }}"},
+			},
+		},
+		"invalid jobs": {
+			data: `
+jobs: [TCID-101]
+`,
+			expectErr: "ci.yml:2: expected a mapping of jobs",
+		},
+		"invalid job": {
+			data: `
+jobs:
+  TCID-101:
+    steps: run
+`,
+			expectErr: "ci.yml:4:",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			workflow, err := ParseGithubWorkflow("ci.yml", []byte(mock.data))
+			if mock.expectErr != "" {
+				if err == nil {
+					t.Fatalf("Expected error %q got none", mock.expectErr)
+				}
+				if !strings.Contains(err.Error(), mock.expectErr) {
+					t.Fatalf("Expected error %q got %q", mock.expectErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			var gotRuns = map[string][]string{}
+			for _, job := range workflow.Jobs {
+				for _, run := range job.Runs() {
+					gotRuns[job.ID] = append(gotRuns[job.ID], run.Name)
+				}
+			}
+			if !reflect.DeepEqual(gotRuns, mock.expectRuns) {
+				t.Fatalf("Expected runs %v got %v", mock.expectRuns, gotRuns)
+			}
+		})
+	}
+}
+
+func TestConfigLoadGithubWorkflows(t *testing.T) {
+	log := &logstesting.TestLogger{
+		T: t,
+	}
+	config := New(LoadableConfig{
+		Path: "testdata/github",
+		Log:  log,
+		Prom: metrics.New(log),
+	})
+	got, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	var gotActual []string
+	for tcid, test := range got.ActualTestCases {
+		if test.Format != FormatGithubActions || test.WorkflowJob == nil {
+			t.Fatalf("Expected github actions test case got %+v", test)
+		}
+		gotActual = append(gotActual, tcid)
+	}
+	sort.Strings(gotActual)
+	var expectActual = []string{
+		"TCID-DIR-HEALTH-CHECK",
+		"TCID-DIR-INSTALL",
+		"TCID-OPENEBS-UPGRADE-CSTOR",
+		"TCID-OPENEBS-UPGRADE-JIVA",
+	}
+	if !reflect.DeepEqual(gotActual, expectActual) {
+		t.Fatalf("Expected actual %v got %v", expectActual, gotActual)
+	}
+	var expectDeprecated = []string{"tcid-dir-legacy-check"}
+	if !reflect.DeepEqual(got.DeprecatedTCIDs(), expectDeprecated) {
+		t.Fatalf(
+			"Expected deprecated %v got %v", expectDeprecated, got.DeprecatedTCIDs(),
+		)
+	}
+	upgrade := got.ActualTestCases["TCID-OPENEBS-UPGRADE-JIVA"]
+	if upgrade.File != ".github/workflows/e2e.yml" || upgrade.Line != 19 {
+		t.Fatalf("Expected test case at .github/workflows/e2e.yml:19 got %+v", upgrade)
+	}
+	if upgrade.Matrix["engine"] != "JIVA" {
+		t.Fatalf("Expected matrix engine JIVA got %v", upgrade.Matrix)
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FormatGitlabCI is the source format of gitlab ci files
+	FormatGitlabCI string = "gitlabci"
+)
+
+func init() {
+	RegisterActualSource(FormatGitlabCI, NewGitlabCISource)
+}
+
+// gitlabCIKeywords are the top level keys of .gitlab-ci.yml that
+// are global keywords & hence can never be a job
+var gitlabCIKeywords = map[string]bool{
+	"default":       true,
+	"include":       true,
+	"stages":        true,
+	"variables":     true,
+	"workflow":      true,
+	"image":         true,
+	"services":      true,
+	"cache":         true,
+	"before_script": true,
+	"after_script":  true,
+	"types":         true,
+}
+
+// GitlabCI is the typed representation of .gitlab-ci.yml
+type GitlabCI struct {
+	Stages []string
+
+	// Jobs has the top level jobs in their order of declaration
+	//
+	// NOTE:
+	//	Hidden jobs i.e. jobs whose names start with a dot are
+	// templates & hence are not part of this list
+	Jobs []*GitlabCIJob
+
+	// Includes has the files that were included while loading
+	// this config
+	Includes []string
+
+	// UnresolvedIncludes has the includes that could not be
+	// resolved e.g. remote or template includes
+	UnresolvedIncludes []string
+}
+
+// GitlabCIJob is a top level job declared in .gitlab-ci.yml
+type GitlabCIJob struct {
+	// Name is the top level key that declares this job
+	Name string `yaml:"-"`
+
+	// File is the config file where this job is declared
+	File string `yaml:"-"`
+
+	// Line is the line where this job is declared
+	Line int `yaml:"-"`
+
+	Extends      StringList           `yaml:"extends"`
+	Stage        string               `yaml:"stage"`
+	Dependencies StringList           `yaml:"dependencies"`
+	Needs        []GitlabCINeed       `yaml:"needs"`
+	Script       StringList           `yaml:"script"`
+	Rules        []GitlabCIRule       `yaml:"rules"`
+	Only         *GitlabCIRefs        `yaml:"only"`
+	Except       *GitlabCIRefs        `yaml:"except"`
+	When         string               `yaml:"when"`
+	AllowFailure GitlabCIAllowFailure `yaml:"allow_failure"`
+}
+
+// GitlabCINeed refers to a job that needs to be completed before
+// the current job can start
+type GitlabCINeed struct {
+	Job       string `yaml:"job"`
+	Artifacts *bool  `yaml:"artifacts"`
+	Optional  bool   `yaml:"optional"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+//
+// NOTE:
+//	A need can either be the name of the job or a mapping with
+// job & its options
+func (n *GitlabCINeed) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		n.Job = node.Value
+		return nil
+	}
+	type plain GitlabCINeed
+	return node.Decode((*plain)(n))
+}
+
+// GitlabCIRule is one of the rules that decides if the job gets
+// added to the pipeline
+type GitlabCIRule struct {
+	If           string               `yaml:"if"`
+	Changes      StringList           `yaml:"changes"`
+	Exists       StringList           `yaml:"exists"`
+	When         string               `yaml:"when"`
+	AllowFailure GitlabCIAllowFailure `yaml:"allow_failure"`
+}
+
+// GitlabCIRefs decides the branches, tags & conditions that either
+// add or exclude the job from the pipeline
+type GitlabCIRefs struct {
+	Refs      StringList `yaml:"refs"`
+	Variables StringList `yaml:"variables"`
+	Changes   StringList `yaml:"changes"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+//
+// NOTE:
+//	only & except can either be a list of refs or a mapping
+func (r *GitlabCIRefs) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return node.Decode(&r.Refs)
+	}
+	type plain GitlabCIRefs
+	return node.Decode((*plain)(r))
+}
+
+// GitlabCIAllowFailure decides if the job is allowed to fail
+type GitlabCIAllowFailure struct {
+	Allowed   bool
+	ExitCodes []int
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+//
+// NOTE:
+//	allow_failure can either be a boolean or a mapping that
+// allows failure for specific exit codes
+func (a *GitlabCIAllowFailure) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&a.Allowed)
+	}
+	var exit struct {
+		ExitCodes yaml.Node `yaml:"exit_codes"`
+	}
+	err := node.Decode(&exit)
+	if err != nil {
+		return err
+	}
+	a.Allowed = true
+	if exit.ExitCodes.Kind == yaml.ScalarNode {
+		var code int
+		err = exit.ExitCodes.Decode(&code)
+		a.ExitCodes = []int{code}
+		return err
+	}
+	return exit.ExitCodes.Decode(&a.ExitCodes)
+}
+
+// StringList is a list of strings that can also be declared as
+// a single string in yaml
+type StringList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+//
+// NOTE:
+//	Nested lists are flattened
+func (l *StringList) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*l = append(*l, node.Value)
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			err := l.UnmarshalYAML(item)
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		var str string
+		// this returns a type error with line number
+		return node.Decode(&str)
+	}
+	return nil
+}
+
+// GitlabCISource loads the actual test cases from the jobs of
+// gitlab ci config
+type GitlabCISource struct {
+	SourceConfig
+}
+
+// NewGitlabCISource returns a new instance of GitlabCISource
+func NewGitlabCISource(conf SourceConfig) (ActualSource, error) {
+	if conf.IncludeFiles == nil {
+		conf.IncludeFiles = conf.Files
+	}
+	return &GitlabCISource{
+		SourceConfig: conf,
+	}, nil
+}
+
+// LoadActual implements ActualSource interface
+//
+// NOTE:
+//	Only the top level jobs that are not hidden are considered as
+// test cases
+func (s *GitlabCISource) LoadActual() ([]ActualTestCase, error) {
+	data, err := s.Files.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	ci, err := ParseGitlabCIWithIncludes(s.Path, data, s.IncludeFiles)
+	if err != nil {
+		return nil, err
+	}
+	var tests []ActualTestCase
+	for _, job := range ci.Jobs {
+		test, ok := newActualTestCase(job.Name)
+		if !ok {
+			continue
+		}
+		test.Format = FormatGitlabCI
+		test.File = job.File
+		test.Line = job.Line
+		test.Job = job
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
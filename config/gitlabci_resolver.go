@@ -0,0 +1,388 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// GitlabCIHiddenJobPrefix is the prefix of jobs that are hidden
+	// i.e. jobs that are used as templates & are never run
+	GitlabCIHiddenJobPrefix string = "."
+
+	// gitlabCIMaxExtendsDepth is the maximum levels of inheritance
+	// supported by gitlab while resolving extends
+	gitlabCIMaxExtendsDepth int = 11
+)
+
+// gitlabCIEntry is a top level job that is yet to be resolved
+type gitlabCIEntry struct {
+	name string
+	file string
+	line int
+
+	// raw has the job's keys with yaml anchors & merge keys
+	// resolved
+	raw map[string]interface{}
+}
+
+// gitlabCIResolver builds gitlab ci config by merging the local
+// includes & by resolving the extends of every job
+type gitlabCIResolver struct {
+	// includes reads the local includes
+	includes FileReader
+
+	// files that were visited so far
+	visited map[string]bool
+
+	ci      *GitlabCI
+	entries []*gitlabCIEntry
+	index   map[string]*gitlabCIEntry
+	errs    FileErrors
+}
+
+// LoadGitlabCI reads the given file along with all its local
+// includes & returns the resolved gitlab ci config. Local includes
+// are resolved against the given root directory.
+func LoadGitlabCI(filename, root string) (*GitlabCI, error) {
+	data, err := NewDirReader("").ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGitlabCIWithIncludes(filename, data, NewDirReader(root))
+}
+
+// ParseGitlabCIWithIncludes decodes the given data along with all
+// its local includes into gitlab ci config. Local includes are
+// read using the given reader.
+func ParseGitlabCIWithIncludes(
+	filename string,
+	data []byte,
+	includes FileReader,
+) (*GitlabCI, error) {
+	r := newGitlabCIResolver(includes)
+	r.visited[filename] = true
+	r.addData(filename, data)
+	return r.resolve()
+}
+
+// ParseGitlabCI decodes the given data into gitlab ci config. Only
+// the top level keys that are not global keywords are considered
+// as jobs.
+//
+// NOTE:
+//	Extends & yaml anchors are resolved. However, includes are not
+// loaded since there is no reader to read them.
+func ParseGitlabCI(filename string, data []byte) (*GitlabCI, error) {
+	return ParseGitlabCIWithIncludes(filename, data, nil)
+}
+
+// newGitlabCIResolver returns a new instance of gitlabCIResolver
+func newGitlabCIResolver(includes FileReader) *gitlabCIResolver {
+	return &gitlabCIResolver{
+		includes: includes,
+		visited:  map[string]bool{},
+		ci:       &GitlabCI{},
+		index:    map[string]*gitlabCIEntry{},
+	}
+}
+
+// addInclude reads the given included file & adds its jobs
+func (r *gitlabCIResolver) addInclude(filename string) {
+	if r.visited[filename] {
+		// this file is already loaded
+		return
+	}
+	r.visited[filename] = true
+	r.ci.Includes = append(r.ci.Includes, filename)
+	data, err := r.includes.ReadFile(filename)
+	if err != nil {
+		r.errs = append(r.errs, newFileError(filename, 0, "%s", err.Error()))
+		return
+	}
+	r.addData(filename, data)
+}
+
+// addData adds the jobs found in the given data. Jobs from the
+// included files are added before the jobs of this file. This lets
+// this file override the included jobs.
+func (r *gitlabCIResolver) addData(filename string, data []byte) {
+	var doc yaml.Node
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		r.errs = appendYAMLError(r.errs, filename, err)
+		return
+	}
+	root := documentRoot(&doc)
+	if root == nil || root.Kind == 0 {
+		// empty file is a valid config without jobs
+		return
+	}
+	if root.Kind != yaml.MappingNode {
+		r.errs = append(
+			r.errs, newFileError(filename, root.Line, "expected a mapping of jobs"),
+		)
+		return
+	}
+	if include := resolveAlias(mappingValue(root, "include")); include != nil {
+		r.addIncludes(filename, include)
+	}
+	for _, pair := range mappingPairs(root) {
+		// jobs can be aliases of other jobs e.g. job: *anchor
+		key, value := pair[0], resolveAlias(pair[1])
+		if key.Value == "stages" {
+			var stages []string
+			err = value.Decode(&stages)
+			if err != nil {
+				r.errs = appendYAMLError(r.errs, filename, err)
+				continue
+			}
+			r.ci.Stages = stages
+			continue
+		}
+		if gitlabCIKeywords[key.Value] || value.Kind != yaml.MappingNode {
+			// global keywords as well as non mapping values
+			// are not jobs
+			continue
+		}
+		// decode to typed job to verify the job against its schema
+		err = value.Decode(&GitlabCIJob{})
+		if err != nil {
+			r.errs = appendYAMLError(r.errs, filename, err)
+			continue
+		}
+		var raw map[string]interface{}
+		err = value.Decode(&raw)
+		if err != nil {
+			r.errs = appendYAMLError(r.errs, filename, err)
+			continue
+		}
+		r.addEntry(&gitlabCIEntry{
+			name: key.Value,
+			file: filename,
+			line: key.Line,
+			raw:  raw,
+		})
+	}
+}
+
+// addEntry adds the given job. A job that is already present gets
+// merged with the given job.
+func (r *gitlabCIResolver) addEntry(entry *gitlabCIEntry) {
+	existing := r.index[entry.name]
+	if existing == nil {
+		r.entries = append(r.entries, entry)
+		r.index[entry.name] = entry
+		return
+	}
+	existing.raw = deepMerge(existing.raw, entry.raw)
+	existing.file = entry.file
+	existing.line = entry.line
+}
+
+// addIncludes loads the local files referred to by the given
+// include node
+//
+// NOTE:
+//	Include can be a string, a mapping or a list of strings &
+// mappings. Remote, project & template includes are not resolved.
+func (r *gitlabCIResolver) addIncludes(filename string, include *yaml.Node) {
+	var items []*yaml.Node
+	if include.Kind == yaml.SequenceNode {
+		items = include.Content
+	} else {
+		items = []*yaml.Node{include}
+	}
+	for _, item := range items {
+		var local string
+		switch item.Kind {
+		case yaml.ScalarNode:
+			local = item.Value
+			if strings.Contains(local, "://") {
+				r.ci.UnresolvedIncludes = append(r.ci.UnresolvedIncludes, local)
+				continue
+			}
+		case yaml.MappingNode:
+			localNode := mappingValue(item, "local")
+			if localNode == nil {
+				var ref map[string]interface{}
+				// error is ignored since this is only used for reporting
+				_ = item.Decode(&ref)
+				for kind, value := range ref {
+					r.ci.UnresolvedIncludes = append(
+						r.ci.UnresolvedIncludes,
+						kind+": "+toString(value),
+					)
+				}
+				continue
+			}
+			local = localNode.Value
+		default:
+			r.errs = append(
+				r.errs, newFileError(filename, item.Line, "invalid include"),
+			)
+			continue
+		}
+		if r.includes == nil {
+			r.ci.UnresolvedIncludes = append(r.ci.UnresolvedIncludes, local)
+			continue
+		}
+		files, err := r.resolveLocal(local)
+		if err != nil {
+			r.errs = append(
+				r.errs, newFileError(filename, item.Line, "include %q: %s", local, err.Error()),
+			)
+			continue
+		}
+		for _, file := range files {
+			r.addInclude(file)
+		}
+	}
+}
+
+// resolveLocal returns the files referred to by the given local
+// include
+//
+// NOTE:
+//	Local includes are relative to the root of the repository. If
+// the included file is not found at this path, the file with same
+// name is looked up at the root itself. This supports flat
+// directories e.g. config map volumes that can not have sub
+// directories. Includes outside the root of the repository are
+// rejected.
+func (r *gitlabCIResolver) resolveLocal(local string) ([]string, error) {
+	rel := path.Clean(strings.TrimPrefix(local, "/"))
+	if isOutsideRoot(rel) {
+		return nil, errOutsideRoot
+	}
+	flat := path.Base(rel)
+	if isGlob(rel) {
+		files, err := globFiles(r.includes, rel)
+		if err != nil || len(files) != 0 || flat == rel {
+			return files, err
+		}
+		return globFiles(r.includes, flat)
+	}
+	_, err := r.includes.ReadFile(rel)
+	if err == nil || !os.IsNotExist(err) || flat == rel {
+		return []string{rel}, nil
+	}
+	if _, err := r.includes.ReadFile(flat); err != nil {
+		return nil, err
+	}
+	return []string{flat}, nil
+}
+
+// resolve builds the gitlab ci config by resolving the extends of
+// every job that is not hidden
+func (r *gitlabCIResolver) resolve() (*GitlabCI, error) {
+	for _, entry := range r.entries {
+		if strings.HasPrefix(entry.name, GitlabCIHiddenJobPrefix) {
+			// hidden jobs are templates
+			continue
+		}
+		raw, err := r.extend(entry, 0)
+		if err != nil {
+			r.errs = append(r.errs, err.(*FileError))
+			continue
+		}
+		var job = &GitlabCIJob{}
+		var node yaml.Node
+		err = node.Encode(raw)
+		if err == nil {
+			err = node.Decode(job)
+		}
+		if err != nil {
+			r.errs = append(
+				r.errs,
+				newFileError(entry.file, entry.line, "invalid job %q: %s", entry.name, err.Error()),
+			)
+			continue
+		}
+		job.Name = entry.name
+		job.File = entry.file
+		job.Line = entry.line
+		r.ci.Jobs = append(r.ci.Jobs, job)
+	}
+	if len(r.errs) != 0 {
+		return nil, r.errs
+	}
+	return r.ci, nil
+}
+
+// extend returns the keys of the given job after merging the keys
+// of the jobs it extends
+func (r *gitlabCIResolver) extend(entry *gitlabCIEntry, depth int) (map[string]interface{}, error) {
+	if depth > gitlabCIMaxExtendsDepth {
+		return nil, newFileError(
+			entry.file, entry.line, "job %q: extends nested too deeply or is circular", entry.name,
+		)
+	}
+	var parents StringList
+	if extends, found := entry.raw["extends"]; found {
+		var node yaml.Node
+		err := node.Encode(extends)
+		if err == nil {
+			err = node.Decode(&parents)
+		}
+		if err != nil {
+			return nil, newFileError(
+				entry.file, entry.line, "job %q: invalid extends: %s", entry.name, err.Error(),
+			)
+		}
+	}
+	var merged = map[string]interface{}{}
+	for _, name := range parents {
+		parent := r.index[name]
+		if parent == nil {
+			return nil, newFileError(
+				entry.file, entry.line, "job %q: extends unknown job %q", entry.name, name,
+			)
+		}
+		raw, err := r.extend(parent, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		merged = deepMerge(merged, raw)
+	}
+	return deepMerge(merged, entry.raw), nil
+}
+
+// deepMerge merges the override into base & returns the merged
+// result. Mappings are merged recursively while other values of
+// override replace the ones in base.
+func deepMerge(base, override map[string]interface{}) map[string]interface{} {
+	var out = make(map[string]interface{}, len(base)+len(override))
+	for key, value := range base {
+		out[key] = value
+	}
+	for key, value := range override {
+		baseMap, isBaseMap := out[key].(map[string]interface{})
+		overrideMap, isOverrideMap := value.(map[string]interface{})
+		if isBaseMap && isOverrideMap {
+			out[key] = deepMerge(baseMap, overrideMap)
+			continue
+		}
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadGitlabCIWithIncludes(t *testing.T) {
+	var tests = map[string]struct {
+		filename         string
+		root             string
+		expectJobs       []*GitlabCIJob
+		expectUnresolved int
+		expectErr        string
+	}{
+		"includes from sub directories": {
+			filename: "testdata/gitlab-include/.gitlab-ci.yml",
+			root:     "testdata/gitlab-include/",
+			expectJobs: []*GitlabCIJob{
+				{
+					Name:         "TCID-DIR-HEALTH-CHECK-V2",
+					File:         "ci/jobs/director.yml",
+					Line:         1,
+					Extends:      StringList{".health-check"},
+					Stage:        "DIRECTOR-HEALTH-CHECK",
+					Dependencies: StringList{"cluster-create"},
+					Script: StringList{
+						"./stages/3-director-sanity-check/od-elasticsearch-logging-check",
+					},
+					AllowFailure: GitlabCIAllowFailure{Allowed: true},
+				},
+				{
+					Name:         "TCID-DIR-HEALTH-CHECK",
+					File:         "testdata/gitlab-include/.gitlab-ci.yml",
+					Line:         23,
+					Extends:      StringList{".health-check"},
+					Stage:        "DIRECTOR-HEALTH-CHECK",
+					Dependencies: StringList{"cluster-create"},
+					Script: StringList{
+						"./stages/3-director-sanity-check/maya-io-server-check",
+					},
+					When:         "manual",
+					AllowFailure: GitlabCIAllowFailure{Allowed: true},
+				},
+				{
+					Name:  "cluster-create",
+					File:  "testdata/gitlab-include/.gitlab-ci.yml",
+					Line:  17,
+					Stage: "CLUSTER-SETUP",
+					Script: StringList{
+						"./stages/1-cluster-setup/gcp",
+					},
+					When: "always",
+				},
+			},
+			expectUnresolved: 2,
+		},
+		"includes from flat directory": {
+			filename: "testdata/gitlab-include-flat/.gitlab-ci.yml",
+			root:     "testdata/gitlab-include-flat",
+			expectJobs: []*GitlabCIJob{
+				{
+					Name:         "TCID-DIR-HEALTH-CHECK",
+					File:         "testdata/gitlab-include-flat/.gitlab-ci.yml",
+					Line:         6,
+					Extends:      StringList{".health-check"},
+					Stage:        "DIRECTOR-HEALTH-CHECK",
+					Dependencies: StringList{"cluster-create"},
+					Script: StringList{
+						"./stages/3-director-sanity-check/maya-io-server-check",
+					},
+					AllowFailure: GitlabCIAllowFailure{Allowed: true},
+				},
+			},
+		},
+		"include outside the root": {
+			filename:  "testdata/gitlab-include-escape/.gitlab-ci.yml",
+			root:      "testdata/gitlab-include-escape",
+			expectErr: "path is outside the root directory",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			ci, err := LoadGitlabCI(mock.filename, mock.root)
+			if mock.expectErr != "" {
+				if err == nil {
+					t.Fatalf("Expected error %q got none", mock.expectErr)
+				}
+				if !strings.Contains(err.Error(), mock.expectErr) {
+					t.Fatalf("Expected error %q got %q", mock.expectErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(ci.Jobs, mock.expectJobs) {
+				t.Fatalf("Expected jobs\n%+v\ngot\n%+v", mock.expectJobs, ci.Jobs)
+			}
+			if len(ci.UnresolvedIncludes) != mock.expectUnresolved {
+				t.Fatalf(
+					"Expected unresolved includes %d got %v",
+					mock.expectUnresolved,
+					ci.UnresolvedIncludes,
+				)
+			}
+		})
+	}
+}
+
+func TestParseGitlabCIExtendsAndAnchors(t *testing.T) {
+	var tests = map[string]struct {
+		data         string
+		expectStages map[string]string
+		expectErr    string
+	}{
+		"hidden jobs are not jobs": {
+			data: `
+.TCID-TEMPLATE:
+  stage: check
+TCID-101:
+  extends: .TCID-TEMPLATE
+`,
+			expectStages: map[string]string{
+				"TCID-101": "check",
+			},
+		},
+		"anchors & merge keys": {
+			data: `
+.base: &base
+  stage: check
+  when: manual
+TCID-101:
+  <<: *base
+TCID-201:
+  <<: *base
+  stage: verify
+`,
+			expectStages: map[string]string{
+				"TCID-101": "check",
+				"TCID-201": "verify",
+			},
+		},
+		"aliases of jobs": {
+			data: `
+.base: &base
+  stage: check
+TCID-101: &upgrade
+  extends: .base
+  variables:
+    TCID: TCID-101
+TCID-201: *base
+TCID-301: *upgrade
+`,
+			expectStages: map[string]string{
+				"TCID-101": "check",
+				"TCID-201": "check",
+				"TCID-301": "check",
+			},
+		},
+		"multi level extends": {
+			data: `
+.base:
+  stage: setup
+.check:
+  extends: .base
+  stage: check
+TCID-101:
+  extends: .check
+TCID-201:
+  extends: [.check, .base]
+`,
+			expectStages: map[string]string{
+				"TCID-101": "check",
+				"TCID-201": "setup",
+			},
+		},
+		"includes are not loaded": {
+			data: `
+include: /ci/templates.yml
+TCID-101:
+  stage: check
+`,
+			expectStages: map[string]string{
+				"TCID-101": "check",
+			},
+		},
+		"extends unknown job": {
+			data: `
+TCID-101:
+  extends: .unknown
+`,
+			expectErr: `ci.yml:2: job "TCID-101": extends unknown job ".unknown"`,
+		},
+		"circular extends": {
+			data: `
+.a:
+  extends: .b
+.b:
+  extends: .a
+TCID-101:
+  extends: .a
+`,
+			expectErr: "extends nested too deeply or is circular",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			ci, err := ParseGitlabCI("ci.yml", []byte(mock.data))
+			if mock.expectErr != "" {
+				if err == nil {
+					t.Fatalf("Expected error %q got none", mock.expectErr)
+				}
+				if !strings.Contains(err.Error(), mock.expectErr) {
+					t.Fatalf("Expected error %q got %q", mock.expectErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			var gotStages = map[string]string{}
+			for _, job := range ci.Jobs {
+				gotStages[job.Name] = job.Stage
+			}
+			if !reflect.DeepEqual(gotStages, mock.expectStages) {
+				t.Fatalf("Expected stages %v got %v", mock.expectStages, gotStages)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGitlabCI(t *testing.T) {
+	var artifacts = false
+	var tests = map[string]struct {
+		data         string
+		expectStages []string
+		expectJobs   []*GitlabCIJob
+		expectErr    string
+	}{
+		"empty file": {
+			data: ``,
+		},
+		"global keywords are not jobs": {
+			data: `
+stages:
+- setup
+variables:
+  TCID-NOT-A-JOB: "true"
+default:
+  image: alpine
+# TCID-COMMENTED-OUT:
+TCID-101:
+  stage: setup
+  script: echo TCID-NOT-A-JOB
+`,
+			expectStages: []string{"setup"},
+			expectJobs: []*GitlabCIJob{
+				{
+					Name:   "TCID-101",
+					File:   "ci.yml",
+					Line:   9,
+					Stage:  "setup",
+					Script: StringList{"echo TCID-NOT-A-JOB"},
+				},
+			},
+		},
+		"nested keys are not jobs": {
+			data: `
+cluster-create:
+  script:
+  - ./setup
+  variables:
+    TCID-NESTED:
+      value: "true"
+`,
+			expectJobs: []*GitlabCIJob{
+				{
+					Name:   "cluster-create",
+					File:   "ci.yml",
+					Line:   2,
+					Script: StringList{"./setup"},
+				},
+			},
+		},
+		"all job details": {
+			data: `
+TCID-101:
+  stage: check
+  dependencies: [setup]
+  needs:
+  - setup
+  - job: deploy
+    artifacts: false
+  script:
+  - chmod 755 ./check
+  - [./check, ./verify]
+  rules:
+  - if: $CI_COMMIT_BRANCH == "master"
+    changes: ["*.go"]
+    when: manual
+    allow_failure: true
+  only:
+  - master
+  except:
+    refs: [tags]
+    variables: [$SKIP]
+  when: always
+  allow_failure:
+    exit_codes: 137
+`,
+			expectJobs: []*GitlabCIJob{
+				{
+					Name:         "TCID-101",
+					File:         "ci.yml",
+					Line:         2,
+					Stage:        "check",
+					Dependencies: StringList{"setup"},
+					Needs: []GitlabCINeed{
+						{Job: "setup"},
+						{Job: "deploy", Artifacts: &artifacts},
+					},
+					Script: StringList{"chmod 755 ./check", "./check", "./verify"},
+					Rules: []GitlabCIRule{
+						{
+							If:      `$CI_COMMIT_BRANCH == "master"`,
+							Changes: StringList{"*.go"},
+							When:    "manual",
+							AllowFailure: GitlabCIAllowFailure{
+								Allowed: true,
+							},
+						},
+					},
+					Only: &GitlabCIRefs{
+						Refs: StringList{"master"},
+					},
+					Except: &GitlabCIRefs{
+						Refs:      StringList{"tags"},
+						Variables: StringList{"$SKIP"},
+					},
+					When: "always",
+					AllowFailure: GitlabCIAllowFailure{
+						Allowed:   true,
+						ExitCodes: []int{137},
+					},
+				},
+			},
+		},
+		"invalid job details": {
+			data: `
+TCID-101:
+  stage: check
+  when:
+    manual: true
+`,
+			expectErr: "ci.yml:5: cannot unmarshal",
+		},
+		"invalid yaml": {
+			data: `
+TCID-101:
+  stage: check
+ when: manual
+`,
+			expectErr: "ci.yml:",
+		},
+		"not a mapping": {
+			data: `
+- TCID-101
+`,
+			expectErr: "ci.yml:2: expected a mapping of jobs",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			ci, err := ParseGitlabCI("ci.yml", []byte(mock.data))
+			if mock.expectErr != "" {
+				if err == nil {
+					t.Fatalf("Expected error %q got none", mock.expectErr)
+				}
+				if !strings.Contains(err.Error(), mock.expectErr) {
+					t.Fatalf("Expected error %q got %q", mock.expectErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(ci.Stages, mock.expectStages) {
+				t.Fatalf("Expected stages %v got %v", mock.expectStages, ci.Stages)
+			}
+			if !reflect.DeepEqual(ci.Jobs, mock.expectJobs) {
+				t.Fatalf("Expected jobs %+v got %+v", mock.expectJobs, ci.Jobs)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	prom "mayadata.io/e2e-metrics/metrics"
+)
+
+const (
+	// FormatGoSource is the source format of go e2e test sources
+	FormatGoSource string = "gosource"
+)
+
+func init() {
+	RegisterActualSource(FormatGoSource, NewGoSource)
+}
+
+// goSpecFuncs are the functions whose first argument describes a
+// test e.g. ginkgo containers & specs as well as go sub tests
+var goSpecFuncs = map[string]bool{
+	"Describe":      true,
+	"Context":       true,
+	"When":          true,
+	"It":            true,
+	"Specify":       true,
+	"DescribeTable": true,
+	"Entry":         true,
+	"Measure":       true,
+	"FDescribe":     true,
+	"FContext":      true,
+	"FWhen":         true,
+	"FIt":           true,
+	"FSpecify":      true,
+	"FEntry":        true,
+	"Run":           true,
+}
+
+// goPendingFuncs are the ginkgo containers & specs that are pending.
+// Specs within a pending container are pending as well.
+var goPendingFuncs = map[string]bool{
+	"PDescribe": true,
+	"PContext":  true,
+	"PWhen":     true,
+	"PIt":       true,
+	"PSpecify":  true,
+	"PEntry":    true,
+	"PMeasure":  true,
+	"XDescribe": true,
+	"XContext":  true,
+	"XWhen":     true,
+	"XIt":       true,
+	"XSpecify":  true,
+	"XEntry":    true,
+	"XMeasure":  true,
+}
+
+// GoTest is a go function or ginkgo node that refers to a test
+// case id
+type GoTest struct {
+	TCID string
+
+	// Name is the test function name or the text of the ginkgo
+	// node
+	Name string
+
+	File string
+	Line int
+}
+
+// ParseGoTests returns the tests found in the given go source
+//
+// NOTE:
+//	Test case ids are looked up in the following:
+//	- doc comments of TestXxx functions
+//	- descriptions of ginkgo containers & specs e.g. Describe & It
+//	- names of sub tests i.e. t.Run
+//
+// Other comments are ignored. Pending ginkgo nodes e.g. PIt or
+// XDescribe are ignored along with the nodes within them. Same test
+// case id found more than once in a file is returned only once.
+func ParseGoTests(filename string, data []byte) ([]GoTest, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, data, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	var tests []GoTest
+	var seen = map[string]bool{}
+	var add = func(text, name string, pos token.Pos) {
+		for _, tcid := range findTestCaseIDs(text) {
+			if seen[tcid] {
+				continue
+			}
+			seen[tcid] = true
+			tests = append(tests, GoTest{
+				TCID: tcid,
+				Name: name,
+				File: filename,
+				Line: fset.Position(pos).Line,
+			})
+		}
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Doc == nil || !strings.HasPrefix(fn.Name.Name, "Test") {
+			continue
+		}
+		add(fn.Name.Name+" "+fn.Doc.Text(), fn.Name.Name, fn.Pos())
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		var name string
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			name = fun.Name
+		case *ast.SelectorExpr:
+			name = fun.Sel.Name
+		}
+		if goPendingFuncs[name] {
+			// nodes within a pending node are pending as well
+			return false
+		}
+		if !goSpecFuncs[name] {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		text, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		add(text, text, call.Pos())
+		return true
+	})
+	return tests, nil
+}
+
+// GoSource loads the actual test cases from go e2e sources e.g.
+// ginkgo specs & go tests
+type GoSource struct {
+	SourceConfig
+}
+
+// NewGoSource returns a new instance of GoSource
+func NewGoSource(conf SourceConfig) (ActualSource, error) {
+	return &GoSource{
+		SourceConfig: conf,
+	}, nil
+}
+
+// LoadActual implements ActualSource interface
+//
+// NOTE:
+//	Every test case found in the go sources is implemented by dope
+func (s *GoSource) LoadActual() ([]ActualTestCase, error) {
+	files, err := s.matchingFilesWithSuffix(".go")
+	if err != nil {
+		return nil, err
+	}
+	var tests []ActualTestCase
+	for _, file := range files {
+		data, err := s.Files.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		goTests, err := ParseGoTests(file, data)
+		if err != nil {
+			return nil, err
+		}
+		for _, goTest := range goTests {
+			test, ok := newActualTestCase(goTest.TCID)
+			if !ok {
+				continue
+			}
+			test.Format = FormatGoSource
+			test.Name = goTest.Name
+			test.File = goTest.File
+			test.Line = goTest.Line
+			test.ImplementationType = prom.TestImplementationTypeDope
+			tests = append(tests, test)
+		}
+	}
+	return tests, nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"mayadata.io/e2e-metrics/metrics"
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+)
+
+func TestParseGoTests(t *testing.T) {
+	var tests = map[string]struct {
+		data        string
+		expectTests []GoTest
+		isErr       bool
+	}{
+		"test function doc": {
+			data: `package e2e
+
+// TestInstall verifies TCID-DIR-INSTALL & TCID-DIR-INSTALL-V2.
+func TestInstall(t *testing.T) {}
+`,
+			expectTests: []GoTest{
+				{TCID: "TCID-DIR-INSTALL", Name: "TestInstall", File: "e2e.go", Line: 4},
+				{TCID: "TCID-DIR-INSTALL-V2", Name: "TestInstall", File: "e2e.go", Line: 4},
+			},
+		},
+		"ginkgo specs": {
+			data: `package e2e
+
+var _ = ginkgo.Describe("backup", func() {
+	ginkgo.It("TCID-DMAAS-BACKUP-", func() {})
+	ginkgo.XIt("TCID-DMAAS-PENDING", func() {})
+})
+`,
+			expectTests: []GoTest{
+				{TCID: "TCID-DMAAS-BACKUP", Name: "TCID-DMAAS-BACKUP-", File: "e2e.go", Line: 4},
+			},
+		},
+		"pending ginkgo specs": {
+			data: `package e2e
+
+var _ = PDescribe("TCID-DMAAS-PENDING", func() {
+	It("TCID-DMAAS-PENDING-BACKUP", func() {})
+})
+
+var _ = Describe("restore", func() {
+	PIt("TCID-DMAAS-PENDING-RESTORE", func() {})
+	XSpecify("TCID-DMAAS-PENDING-MIGRATE", func() {})
+})
+`,
+		},
+		"comments that are not test function docs": {
+			data: `package e2e
+
+// TCID-DMAAS-HELPER
+func backup() {}
+
+var _ = Describe("restore", func() {
+	// TCID-DMAAS-RESTORE
+	It("restores", func() {})
+})
+`,
+		},
+		"same tcid more than once": {
+			data: `package e2e
+
+var _ = Describe("TCID-DMAAS-RESTORE", func() {
+	It("TCID-DMAAS-RESTORE", func() {})
+})
+`,
+			expectTests: []GoTest{
+				{TCID: "TCID-DMAAS-RESTORE", Name: "TCID-DMAAS-RESTORE", File: "e2e.go", Line: 3},
+			},
+		},
+		"invalid go source": {
+			data:  `package`,
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseGoTests("e2e.go", []byte(mock.data))
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(got, mock.expectTests) {
+				t.Fatalf("Expected tests\n%+v\ngot\n%+v", mock.expectTests, got)
+			}
+		})
+	}
+}
+
+func TestConfigLoadGoSource(t *testing.T) {
+	log := &logstesting.TestLogger{
+		T: t,
+	}
+	config := New(LoadableConfig{
+		Path: "testdata/gosource",
+		Log:  log,
+		Prom: metrics.New(log),
+		ActualSources: []SourceConfig{
+			{Format: FormatGoSource, Path: "."},
+		},
+	})
+	got, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	var expectActual = map[string]int{
+		"TCID-DIR-SUITE":             12,
+		"TCID-DIR-HEALTH-CHECK":      16,
+		"TCID-DIR-INSTALL":           20,
+		"TCID-OPENEBS-UPGRADE-CSTOR": 6,
+	}
+	if len(got.ActualTestCases) != len(expectActual) {
+		t.Fatalf("Expected actual %v got %+v", expectActual, got.ActualTestCases)
+	}
+	for tcid, line := range expectActual {
+		test, found := got.ActualTestCases[tcid]
+		if !found {
+			t.Fatalf("Expected actual tcid %q got %+v", tcid, got.ActualTestCases)
+		}
+		if test.Line != line {
+			t.Fatalf("Expected tcid %q at line %d got %d", tcid, line, test.Line)
+		}
+		if test.ImplementationType != metrics.TestImplementationTypeDope {
+			t.Fatalf(
+				"Expected tcid %q implemented by %q got %q",
+				tcid,
+				metrics.TestImplementationTypeDope,
+				test.ImplementationType,
+			)
+		}
+	}
+	var expectDeprecated = []string{"tcid-openebs-upgrade-jiva"}
+	if !reflect.DeepEqual(got.DeprecatedTCIDs(), expectDeprecated) {
+		t.Fatalf(
+			"Expected deprecated %v got %v", expectDeprecated, got.DeprecatedTCIDs(),
+		)
+	}
+}
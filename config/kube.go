@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// TestCaseIDLabelKey is the label that can be set against the
+	// kubernetes resources e.g. tekton tasks or argo templates to
+	// mark them as the implementation of a test case
+	//
+	// NOTE:
+	//	Value of this label is the test case id e.g.
+	//
+	//	metadata:
+	//	  labels:
+	//	    e2e.mayadata.io/tcid: TCID-DIR-HEALTH-CHECK
+	TestCaseIDLabelKey string = "e2e.mayadata.io/tcid"
+)
+
+// KubeObjectMeta has the identifying details of a kubernetes
+// resource
+type KubeObjectMeta struct {
+	Name         string            `yaml:"name"`
+	GenerateName string            `yaml:"generateName"`
+	Namespace    string            `yaml:"namespace"`
+	Labels       map[string]string `yaml:"labels"`
+}
+
+// NameOrGenerateName returns the name of the resource or its
+// generate name if the resource is not named
+func (m KubeObjectMeta) NameOrGenerateName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.GenerateName
+}
+
+// kubeResource is a kubernetes resource that is declared in a yaml
+// file
+type kubeResource struct {
+	APIVersion string         `yaml:"apiVersion"`
+	Kind       string         `yaml:"kind"`
+	Metadata   KubeObjectMeta `yaml:"metadata"`
+
+	// File is the file where this resource is declared
+	File string `yaml:"-"`
+
+	// node is the yaml node of this resource that can be decoded
+	// into its typed representation
+	node *yaml.Node
+}
+
+// String implements Stringer interface
+func (r *kubeResource) String() string {
+	return r.Kind + "/" + r.Metadata.NameOrGenerateName()
+}
+
+// decode decodes this resource into the given typed resource
+func (r *kubeResource) decode(out interface{}) error {
+	return wrapYAMLError(r.File, r.node.Decode(out))
+}
+
+// parseKubeResources returns the kubernetes resources found in the
+// given data
+//
+// NOTE:
+//	Data can have multiple yaml documents. Items of a kubernetes
+// List are returned as individual resources.
+func parseKubeResources(filename string, data []byte) ([]*kubeResource, error) {
+	var resources []*kubeResource
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, wrapYAMLError(filename, err)
+		}
+		root := documentRoot(&doc)
+		if root == nil || root.Kind == 0 {
+			// empty documents are ignored
+			continue
+		}
+		found, err := decodeKubeResources(filename, root)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, found...)
+	}
+	return resources, nil
+}
+
+// decodeKubeResources returns the kubernetes resources found in the
+// given yaml document node
+func decodeKubeResources(filename string, root *yaml.Node) ([]*kubeResource, error) {
+	var nodes = []*yaml.Node{root}
+	if items := mappingValue(root, "items"); items != nil &&
+		items.Kind == yaml.SequenceNode {
+		nodes = items.Content
+	}
+	var resources []*kubeResource
+	for _, node := range nodes {
+		var resource = &kubeResource{
+			File: filename,
+			node: node,
+		}
+		err := resource.decode(resource)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// loadKubeResources returns the kubernetes resources found in the
+// file(s) of the given source config
+func loadKubeResources(conf SourceConfig) ([]*kubeResource, error) {
+	files, err := conf.matchingFiles()
+	if err != nil {
+		return nil, err
+	}
+	var resources []*kubeResource
+	for _, file := range files {
+		data, err := conf.Files.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		found, err := parseKubeResources(file, data)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, found...)
+	}
+	return resources, nil
+}
+
+// newLabelledActualTestCase returns the actual test case if the
+// given labels have the test case id label or if the given name has
+// the test case id prefix. Label takes precedence over the name.
+func newLabelledActualTestCase(name string, labels map[string]string) (ActualTestCase, bool) {
+	if tcid := labels[TestCaseIDLabelKey]; tcid != "" {
+		if test, ok := newActualTestCase(tcid); ok {
+			return test, true
+		}
+	}
+	return newActualTestCase(name)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKubeSourcesLoadActual(t *testing.T) {
+	var tests = map[string]struct {
+		conf        SourceConfig
+		expectTests []ActualTestCase
+		expectErr   bool
+	}{
+		"tekton pipeline & tasks": {
+			conf: SourceConfig{
+				Format: FormatTekton,
+				Path:   "tekton/*.yaml",
+			},
+			expectTests: []ActualTestCase{
+				{
+					TCID:     "TCID-DIR-HEALTH-CHECK",
+					Format:   FormatTekton,
+					File:     "tekton/pipeline.yaml",
+					Line:     1,
+					Resource: "Task/director-health-check",
+				},
+				{
+					TCID:     "TCID-DIR-INSTALL",
+					Format:   FormatTekton,
+					File:     "tekton/pipeline.yaml",
+					Line:     40,
+					Resource: "Pipeline/e2e",
+				},
+				{
+					TCID:     "TCID-OPENEBS-UPGRADE",
+					Format:   FormatTekton,
+					File:     "tekton/pipeline.yaml",
+					Line:     44,
+					Resource: "Pipeline/e2e",
+				},
+				{
+					TCID:       "tcid-cluster-cleanup",
+					Format:     FormatTekton,
+					File:       "tekton/pipeline.yaml",
+					Line:       54,
+					Resource:   "Pipeline/e2e",
+					Deprecated: true,
+				},
+			},
+		},
+		"tekton task referred from another file": {
+			conf: SourceConfig{
+				Format: FormatTekton,
+				Path:   "tekton/split/*.yaml",
+			},
+			expectTests: []ActualTestCase{
+				{
+					TCID:     "TCID-DIR-HEALTH-CHECK",
+					Format:   FormatTekton,
+					File:     "tekton/split/task.yaml",
+					Line:     1,
+					Resource: "Task/director-health-check",
+				},
+			},
+		},
+		"argo workflows": {
+			conf: SourceConfig{
+				Format: FormatArgo,
+				Path:   "argo/workflow.yaml",
+			},
+			expectTests: []ActualTestCase{
+				{
+					TCID:     "TCID-DIR-HEALTH-CHECK",
+					Format:   FormatArgo,
+					File:     "argo/workflow.yaml",
+					Line:     16,
+					Resource: "Workflow/e2e-",
+				},
+				{
+					TCID:     "TCID-DIR-INSTALL",
+					Format:   FormatArgo,
+					File:     "argo/workflow.yaml",
+					Line:     12,
+					Resource: "Workflow/e2e-",
+				},
+				{
+					TCID:     "TCID-OPENEBS-UPGRADE",
+					Format:   FormatArgo,
+					File:     "argo/workflow.yaml",
+					Line:     41,
+					Resource: "CronWorkflow/nightly",
+				},
+			},
+		},
+		"argo template referred from another file": {
+			conf: SourceConfig{
+				Format: FormatArgo,
+				Path:   "argo/split/*.yaml",
+			},
+			expectTests: []ActualTestCase{
+				{
+					TCID:     "TCID-DIR-HEALTH-CHECK",
+					Format:   FormatArgo,
+					File:     "argo/split/template.yaml",
+					Line:     7,
+					Resource: "WorkflowTemplate/director",
+				},
+			},
+		},
+		"missing file": {
+			conf: SourceConfig{
+				Format: FormatArgo,
+				Path:   "argo/missing.yaml",
+			},
+			expectErr: true,
+		},
+		"file outside the root": {
+			conf: SourceConfig{
+				Format: FormatArgo,
+				Path:   "../testdata/argo/workflow.yaml",
+			},
+			expectErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			mock.conf.Files = NewDirReader("testdata")
+			source, err := NewActualSource(mock.conf)
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			got, err := source.LoadActual()
+			if mock.expectErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.expectErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(got, mock.expectTests) {
+				t.Fatalf("Expected tests\n%+v\ngot\n%+v", mock.expectTests, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,402 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+
+	prom "mayadata.io/e2e-metrics/metrics"
+)
+
+const (
+	// FormatLitmus is the source format of litmusbooks & their
+	// ansible test playbooks
+	FormatLitmus string = "litmus"
+
+	// KindLitmusJob is the kind of kubernetes resource that runs a
+	// litmusbook
+	KindLitmusJob string = "Job"
+)
+
+func init() {
+	RegisterActualSource(FormatLitmus, NewLitmusSource)
+}
+
+// LitmusJob is the typed representation of a litmusbook i.e. a
+// kubernetes job that runs the litmus test
+//
+// NOTE:
+//	A sample litmusbook looks like below:
+//
+//	apiVersion: batch/v1
+//	kind: Job
+//	metadata:
+//	  generateName: openebs-upgrade-
+//	  labels:
+//	    e2e.mayadata.io/tcid: TCID-OPENEBS-UPGRADE
+//	spec:
+//	  template:
+//	    spec:
+//	      containers:
+//	      - name: ansibletest
+//	        env:
+//	        - name: TCID
+//	          value: TCID-OPENEBS-UPGRADE
+type LitmusJob struct {
+	Metadata KubeObjectMeta `yaml:"metadata"`
+	Spec     struct {
+		Template struct {
+			Metadata KubeObjectMeta `yaml:"metadata"`
+			Spec     struct {
+				Containers []LitmusContainer `yaml:"containers"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}
+
+// LitmusContainer is one of the containers of a litmusbook
+type LitmusContainer struct {
+	Name string      `yaml:"name"`
+	Env  []LitmusEnv `yaml:"env"`
+}
+
+// LitmusEnv is an environment variable of a litmusbook container
+type LitmusEnv struct {
+	// Line is the line where this variable is declared
+	Line int `yaml:"-"`
+
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (e *LitmusEnv) UnmarshalYAML(node *yaml.Node) error {
+	type plain LitmusEnv
+	err := node.Decode((*plain)(e))
+	if err != nil {
+		return err
+	}
+	e.Line = node.Line
+	return nil
+}
+
+// AnsiblePlay is one of the plays of an ansible test playbook
+//
+// NOTE:
+//	A sample playbook looks like below:
+//
+//	- hosts: localhost
+//	  tasks:
+//	  - name: TCID-OPENEBS-UPGRADE verify the upgraded pools
+//	    shell: kubectl get csp
+//	  - block:
+//	    - name: "TCID-OPENEBS-UPGRADE-JIVA: verify jiva volumes"
+//	      shell: kubectl get pv
+type AnsiblePlay struct {
+	// Line is the line where this play is declared
+	Line int `yaml:"-"`
+
+	Name      string        `yaml:"name"`
+	Hosts     StringList    `yaml:"hosts"`
+	PreTasks  []AnsibleTask `yaml:"pre_tasks"`
+	Tasks     []AnsibleTask `yaml:"tasks"`
+	PostTasks []AnsibleTask `yaml:"post_tasks"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (p *AnsiblePlay) UnmarshalYAML(node *yaml.Node) error {
+	type plain AnsiblePlay
+	err := node.Decode((*plain)(p))
+	if err != nil {
+		return err
+	}
+	p.Line = node.Line
+	return nil
+}
+
+// AnsibleTask is a task of an ansible play. A task can be a block
+// of tasks.
+type AnsibleTask struct {
+	// Line is the line where this task is declared
+	Line int `yaml:"-"`
+
+	Name   string        `yaml:"name"`
+	Block  []AnsibleTask `yaml:"block"`
+	Rescue []AnsibleTask `yaml:"rescue"`
+	Always []AnsibleTask `yaml:"always"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (t *AnsibleTask) UnmarshalYAML(node *yaml.Node) error {
+	type plain AnsibleTask
+	err := node.Decode((*plain)(t))
+	if err != nil {
+		return err
+	}
+	t.Line = node.Line
+	return nil
+}
+
+// LitmusTest is a litmusbook or an ansible task that refers to a
+// test case id
+type LitmusTest struct {
+	TCID string
+
+	// Name is the name of the litmusbook or the ansible task
+	Name string
+
+	File string
+	Line int
+}
+
+// litmusParser finds the litmus tests of a single file
+type litmusParser struct {
+	file  string
+	seen  map[string]bool
+	tests []LitmusTest
+}
+
+// add adds the test case ids found in the given text as tests
+func (p *litmusParser) add(text, name string, line int) {
+	for _, tcid := range findTestCaseIDs(text) {
+		if p.seen[tcid] {
+			continue
+		}
+		p.seen[tcid] = true
+		p.tests = append(p.tests, LitmusTest{
+			TCID: tcid,
+			Name: name,
+			File: p.file,
+			Line: line,
+		})
+	}
+}
+
+// addJob adds the test case ids found in the labels & env
+// variables of the given litmusbook
+func (p *litmusParser) addJob(resource *kubeResource) error {
+	var job LitmusJob
+	err := resource.decode(&job)
+	if err != nil {
+		return err
+	}
+	name := resource.Metadata.NameOrGenerateName()
+	for _, labels := range []map[string]string{
+		job.Metadata.Labels,
+		job.Spec.Template.Metadata.Labels,
+	} {
+		p.add(labels[TestCaseIDLabelKey], name, resource.node.Line)
+	}
+	for _, container := range job.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			p.add(env.Value, name, env.Line)
+		}
+	}
+	return nil
+}
+
+// addJobs adds the test case ids found in the litmusbooks of the
+// given yaml document
+//
+// NOTE:
+//	Nodes that are not kubernetes resources e.g. ansible vars with
+// items or metadata of some other shape are ignored. Litmusbooks
+// that can not be decoded result in an error.
+func (p *litmusParser) addJobs(root *yaml.Node) error {
+	var nodes = []*yaml.Node{root}
+	if items := mappingValue(root, "items"); items != nil &&
+		items.Kind == yaml.SequenceNode {
+		nodes = items.Content
+	}
+	for _, node := range nodes {
+		var resource = &kubeResource{
+			File: p.file,
+			node: node,
+		}
+		err := resource.decode(resource)
+		if err != nil && isLitmusJob(node) {
+			return err
+		}
+		if err != nil || resource.Kind != KindLitmusJob {
+			continue
+		}
+		err = p.addJob(resource)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isLitmusJob returns true if the given yaml node is of the kind of
+// a litmusbook
+func isLitmusJob(node *yaml.Node) bool {
+	kind := mappingValue(node, "kind")
+	return kind != nil && kind.Value == KindLitmusJob
+}
+
+// addTasks adds the test case ids found in the names of the given
+// tasks & their blocks
+func (p *litmusParser) addTasks(tasks []AnsibleTask) {
+	for _, task := range tasks {
+		p.add(task.Name, task.Name, task.Line)
+		p.addTasks(task.Block)
+		p.addTasks(task.Rescue)
+		p.addTasks(task.Always)
+	}
+}
+
+// addPlaybook adds the test case ids found in the names of the
+// plays & tasks of the given playbook
+//
+// NOTE:
+//	Task files i.e. files that are included by playbooks are lists
+// of tasks instead of plays. Hence every item is handled as a play
+// as well as a task. Items that are not mappings or are of some
+// other shape e.g. a list of vars are ignored.
+func (p *litmusParser) addPlaybook(root *yaml.Node) {
+	for _, item := range root.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		var play AnsiblePlay
+		if item.Decode(&play) == nil {
+			p.addTasks(play.PreTasks)
+			p.addTasks(play.Tasks)
+			p.addTasks(play.PostTasks)
+		}
+		var task AnsibleTask
+		if item.Decode(&task) == nil {
+			p.addTasks([]AnsibleTask{task})
+		}
+	}
+}
+
+// ParseLitmusTests returns the litmus tests found in the given
+// data
+//
+// NOTE:
+//	Test case ids are looked up in the following:
+//	- TestCaseIDLabelKey label of litmusbook jobs & their pods
+//	- env variables of litmusbook containers
+//	- names of ansible plays & tasks
+//
+// A yaml document that is a list is considered as an ansible
+// playbook. Kubernetes resources other than jobs are ignored. Same
+// test case id found more than once in a file is returned only once.
+//
+// Documents that are not valid yaml e.g. jinja templates are
+// ignored since litmus repos have these alongside the tests. Only
+// litmusbooks that can not be decoded result in an error.
+func ParseLitmusTests(filename string, data []byte) ([]LitmusTest, error) {
+	var p = &litmusParser{
+		file: filename,
+		seen: map[string]bool{},
+	}
+	for _, document := range splitYAMLDocuments(data) {
+		var doc yaml.Node
+		err := yaml.Unmarshal(document, &doc)
+		if err != nil {
+			continue
+		}
+		root := documentRoot(&doc)
+		if root == nil || root.Kind == 0 {
+			// empty documents are ignored
+			continue
+		}
+		switch root.Kind {
+		case yaml.SequenceNode:
+			p.addPlaybook(root)
+		case yaml.MappingNode:
+			err = p.addJobs(root)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p.tests, nil
+}
+
+// splitYAMLDocuments returns the yaml documents of the given data.
+// Each document is prefixed with the lines of the documents before
+// it as empty lines. Hence, lines of the nodes of a document are
+// same as their lines in the data.
+func splitYAMLDocuments(data []byte) [][]byte {
+	var documents [][]byte
+	var current []byte
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	for i, line := range lines {
+		if i > 0 && bytes.HasPrefix(line, []byte("---")) {
+			documents = append(documents, current)
+			current = bytes.Repeat([]byte("\n"), i)
+		}
+		current = append(current, line...)
+	}
+	return append(documents, current)
+}
+
+// LitmusSource loads the actual test cases from litmusbooks &
+// ansible test playbooks
+type LitmusSource struct {
+	SourceConfig
+}
+
+// NewLitmusSource returns a new instance of LitmusSource
+func NewLitmusSource(conf SourceConfig) (ActualSource, error) {
+	return &LitmusSource{
+		SourceConfig: conf,
+	}, nil
+}
+
+// LoadActual implements ActualSource interface
+//
+// NOTE:
+//	Path can be a directory e.g. a checkout of the litmus repo, a
+// file or a glob pattern. Every test case found is implemented by
+// litmus.
+func (s *LitmusSource) LoadActual() ([]ActualTestCase, error) {
+	files, err := s.matchingFilesWithSuffix(".yml", ".yaml")
+	if err != nil {
+		return nil, err
+	}
+	var tests []ActualTestCase
+	for _, file := range files {
+		data, err := s.Files.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		litmusTests, err := ParseLitmusTests(file, data)
+		if err != nil {
+			return nil, err
+		}
+		for _, litmusTest := range litmusTests {
+			test, ok := newActualTestCase(litmusTest.TCID)
+			if !ok {
+				continue
+			}
+			test.Format = FormatLitmus
+			test.Name = litmusTest.Name
+			test.File = litmusTest.File
+			test.Line = litmusTest.Line
+			test.ImplementationType = prom.TestImplementationTypeLitmus
+			tests = append(tests, test)
+		}
+	}
+	return tests, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"mayadata.io/e2e-metrics/metrics"
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+)
+
+func TestParseLitmusTests(t *testing.T) {
+	var tests = map[string]struct {
+		data        string
+		expectTests []LitmusTest
+		isErr       bool
+	}{
+		"litmusbook labels & env": {
+			data: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: openebs-upgrade
+spec:
+  template:
+    metadata:
+      labels:
+        e2e.mayadata.io/tcid: TCID-OPENEBS-UPGRADE
+    spec:
+      containers:
+      - name: ansibletest
+        env:
+        - name: TCID
+          value: TCID-OPENEBS-UPGRADE-JIVA
+`,
+			expectTests: []LitmusTest{
+				{TCID: "TCID-OPENEBS-UPGRADE", Name: "openebs-upgrade", File: "test.yml", Line: 2},
+				{TCID: "TCID-OPENEBS-UPGRADE-JIVA", Name: "openebs-upgrade", File: "test.yml", Line: 15},
+			},
+		},
+		"other kubernetes resources": {
+			data: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: TCID-OPENEBS-UPGRADE
+  labels:
+    e2e.mayadata.io/tcid: TCID-OPENEBS-UPGRADE
+`,
+		},
+		"playbook tasks & blocks": {
+			data: `
+- hosts: localhost
+  tasks:
+  - name: TCID-DMAAS-BACKUP take a backup
+  - block:
+    - name: "[TCID-DMAAS-RESTORE] restore the backup"
+    always:
+    - name: TCID-DMAAS-BACKUP cleanup
+`,
+			expectTests: []LitmusTest{
+				{TCID: "TCID-DMAAS-BACKUP", Name: "TCID-DMAAS-BACKUP take a backup", File: "test.yml", Line: 4},
+				{TCID: "TCID-DMAAS-RESTORE", Name: "[TCID-DMAAS-RESTORE] restore the backup", File: "test.yml", Line: 6},
+			},
+		},
+		"playbook of unexpected shape": {
+			data: `
+- hosts: localhost
+  tasks: run
+`,
+		},
+		"jinja template before a litmusbook": {
+			data: `
+- hosts: localhost
+  tasks:
+  {% for test in tests %}
+  - name: {{ test }}
+  {% endfor %}
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: openebs-upgrade
+  labels:
+    e2e.mayadata.io/tcid: TCID-OPENEBS-UPGRADE
+`,
+			expectTests: []LitmusTest{
+				{TCID: "TCID-OPENEBS-UPGRADE", Name: "openebs-upgrade", File: "test.yml", Line: 8},
+			},
+		},
+		"vars with items & metadata": {
+			data: `
+items:
+- TCID-NOT-A-TEST
+metadata: openebs
+---
+items: [1, 2]
+`,
+		},
+		"invalid litmusbook": {
+			data: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: openebs-upgrade
+spec:
+  template:
+    spec:
+      containers: ansibletest
+`,
+			isErr: true,
+		},
+		"litmusbook with invalid metadata": {
+			data: `
+apiVersion: batch/v1
+kind: Job
+metadata: openebs-upgrade
+`,
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseLitmusTests("test.yml", []byte(mock.data))
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(got, mock.expectTests) {
+				t.Fatalf("Expected tests\n%+v\ngot\n%+v", mock.expectTests, got)
+			}
+		})
+	}
+}
+
+func TestConfigLoadLitmus(t *testing.T) {
+	log := &logstesting.TestLogger{
+		T: t,
+	}
+	config := New(LoadableConfig{
+		Path: "testdata/litmus",
+		Log:  log,
+		Prom: metrics.New(log),
+		ActualSources: []SourceConfig{
+			{Format: FormatLitmus, Path: "."},
+		},
+	})
+	got, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	var expectActual = map[string]string{
+		"TCID-DIR-HEALTH-CHECK":      "director/health-check/test.yml",
+		"TCID-DIR-HEALTH-CHECK-V2":   "director/health-check/run_litmus_test.yml",
+		"TCID-OPENEBS-UPGRADE":       "openebs/upgrade/test.yml",
+		"TCID-OPENEBS-UPGRADE-CSTOR": "openebs/upgrade/test.yml",
+		"TCID-OPENEBS-UPGRADE-JIVA":  "openebs/upgrade/verify.yml",
+	}
+	if len(got.ActualTestCases) != len(expectActual) {
+		t.Fatalf("Expected actual %v got %+v", expectActual, got.ActualTestCases)
+	}
+	for tcid, file := range expectActual {
+		test, found := got.ActualTestCases[tcid]
+		if !found {
+			t.Fatalf("Expected actual tcid %q got %+v", tcid, got.ActualTestCases)
+		}
+		if test.File != file {
+			t.Fatalf("Expected tcid %q in file %q got %q", tcid, file, test.File)
+		}
+		if test.ImplementationType != metrics.TestImplementationTypeLitmus {
+			t.Fatalf(
+				"Expected tcid %q implemented by %q got %q",
+				tcid,
+				metrics.TestImplementationTypeLitmus,
+				test.ImplementationType,
+			)
+		}
+	}
+	// litmusbook & ansible playbook of the same experiment implement
+	// the same test case
+	if len(got.DuplicateTestCases) != 0 {
+		t.Fatalf("Expected no duplicates got %v", got.DuplicateTestCases)
+	}
+	var expectDeprecated = []string{"tcid-openebs-upgrade-rollback"}
+	if !reflect.DeepEqual(got.DeprecatedTCIDs(), expectDeprecated) {
+		t.Fatalf(
+			"Expected deprecated %v got %v", expectDeprecated, got.DeprecatedTCIDs(),
+		)
+	}
+}
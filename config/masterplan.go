@@ -0,0 +1,202 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// KindMasterPlan is the kind of the yaml document that
+	// has all the desired test cases
+	KindMasterPlan string = "MasterPlan"
+
+	// FormatMasterPlan is the source format of master plan files
+	FormatMasterPlan string = "masterplan"
+)
+
+func init() {
+	RegisterDesiredSource(FormatMasterPlan, NewMasterPlanSource)
+}
+
+// MasterPlan is the typed representation of .master-plan.yml
+//
+// NOTE:
+//	A sample master plan looks like below:
+//
+//	kind: MasterPlan
+//	apiVersion: e2e.mayadata.io/v1alpha1
+//	metadata:
+//	  name: gitlab-testCases
+//	spec:
+//	  tests:
+//	  - tcid: TCID-DIR-HEALTH-CHECK
+//	    name: Install on a K8S Cluster
+//	    description: Verify the health of director components
+//	    labels:
+//	      test/group: Install and Upgrade of OpenEBS
+type MasterPlan struct {
+	Kind       string             `yaml:"kind"`
+	APIVersion string             `yaml:"apiVersion"`
+	Metadata   MasterPlanMetadata `yaml:"metadata"`
+	Spec       MasterPlanSpec     `yaml:"spec"`
+}
+
+// MasterPlanMetadata has the identifying details of a master plan
+type MasterPlanMetadata struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// MasterPlanSpec has the list of planned tests
+type MasterPlanSpec struct {
+	Tests []PlannedTest `yaml:"tests"`
+}
+
+const (
+	// PlannedTestGroupLabelKey is the label of a planned test that
+	// has the group of this test e.g. upgrade
+	PlannedTestGroupLabelKey string = "test/group"
+
+	// PlannedTestTypeLabelKey is the label of a planned test that
+	// has the type of this test e.g. functional
+	PlannedTestTypeLabelKey string = "test/type"
+)
+
+// PlannedTest is a test case that is registered in master plan
+type PlannedTest struct {
+	TCID        string            `yaml:"tcid"`
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Labels      map[string]string `yaml:"labels"`
+
+	// Line is the line in master plan file where this test
+	// case is declared
+	Line int `yaml:"-"`
+}
+
+// MasterPlanSource loads the desired test cases from master plan
+// file(s)
+type MasterPlanSource struct {
+	SourceConfig
+}
+
+// NewMasterPlanSource returns a new instance of MasterPlanSource
+func NewMasterPlanSource(conf SourceConfig) (DesiredSource, error) {
+	return &MasterPlanSource{
+		SourceConfig: conf,
+	}, nil
+}
+
+// LoadDesired implements DesiredSource interface
+//
+// NOTE:
+//	Path can be a glob pattern to load multiple master plans
+func (s *MasterPlanSource) LoadDesired() ([]PlannedTest, error) {
+	files, err := s.matchingFiles()
+	if err != nil {
+		return nil, err
+	}
+	var tests []PlannedTest
+	for _, file := range files {
+		data, err := s.Files.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		plan, err := ParseMasterPlan(file, data)
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, plan.Spec.Tests...)
+	}
+	return tests, nil
+}
+
+// LoadMasterPlan reads the given file & returns the master plan
+// found in this file
+func LoadMasterPlan(filename string) (*MasterPlan, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMasterPlan(filename, data)
+}
+
+// ParseMasterPlan decodes the given data into a master plan. Data
+// that does not match the master plan schema results in error that
+// points to the offending line of the given file.
+func ParseMasterPlan(filename string, data []byte) (*MasterPlan, error) {
+	var plan MasterPlan
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	// unknown fields are treated as errors
+	decoder.KnownFields(true)
+	err := decoder.Decode(&plan)
+	if err == io.EOF {
+		return nil, newFileError(filename, 0, "no master plan found")
+	}
+	if err != nil {
+		return nil, wrapYAMLError(filename, err)
+	}
+
+	// decode once again to get hold of line numbers
+	var doc yaml.Node
+	err = yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, wrapYAMLError(filename, err)
+	}
+	root := documentRoot(&doc)
+
+	var errs FileErrors
+	if plan.Kind != KindMasterPlan {
+		line := root.Line
+		if kindKey, _ := mappingEntry(root, "kind"); kindKey != nil {
+			line = kindKey.Line
+		}
+		errs = append(
+			errs,
+			newFileError(
+				filename, line, "invalid kind %q: want %q", plan.Kind, KindMasterPlan,
+			),
+		)
+	}
+	tests := mappingValue(mappingValue(root, "spec"), "tests")
+	for i := range plan.Spec.Tests {
+		if tests != nil && i < len(tests.Content) {
+			plan.Spec.Tests[i].Line = tests.Content[i].Line
+		}
+		plan.Spec.Tests[i].TCID = strings.TrimSpace(plan.Spec.Tests[i].TCID)
+		if plan.Spec.Tests[i].TCID == "" {
+			errs = append(
+				errs,
+				newFileError(
+					filename, plan.Spec.Tests[i].Line, "missing tcid in spec.tests[%d]", i,
+				),
+			)
+		}
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return &plan, nil
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMasterPlan(t *testing.T) {
+	var tests = map[string]struct {
+		data        string
+		expectTests []PlannedTest
+		expectErr   string
+	}{
+		"all fields": {
+			data: `
+kind: MasterPlan
+apiVersion: e2e.mayadata.io/v1alpha1
+metadata:
+  name: testplan
+spec:
+  tests:
+  - tcid: TCID-101
+    name: Install
+    description: Install all components
+    labels:
+      test/group: install
+`,
+			expectTests: []PlannedTest{
+				{
+					TCID:        "TCID-101",
+					Name:        "Install",
+					Description: "Install all components",
+					Labels: map[string]string{
+						"test/group": "install",
+					},
+					Line: 8,
+				},
+			},
+		},
+		"quoted values & different key order": {
+			data: `
+spec:
+  tests:
+  - name: "Install"
+    tcid: "TCID-101"
+  - labels: {test/group: 'upgrade'}
+    tcid: 'TCID-201'
+kind: MasterPlan
+`,
+			expectTests: []PlannedTest{
+				{
+					TCID: "TCID-101",
+					Name: "Install",
+					Line: 4,
+				},
+				{
+					TCID: "TCID-201",
+					Labels: map[string]string{
+						"test/group": "upgrade",
+					},
+					Line: 6,
+				},
+			},
+		},
+		"inline maps": {
+			data: `
+kind: MasterPlan
+spec: {tests: [{tcid: TCID-101, name: Install}]}
+`,
+			expectTests: []PlannedTest{
+				{
+					TCID: "TCID-101",
+					Name: "Install",
+					Line: 3,
+				},
+			},
+		},
+		"empty file": {
+			data:      ``,
+			expectErr: "plan.yml: no master plan found",
+		},
+		"invalid kind": {
+			data: `
+kind: GitlabCI
+spec:
+  tests:
+  - tcid: TCID-101
+`,
+			expectErr: "plan.yml:2: invalid kind",
+		},
+		"missing tcid": {
+			data: `
+kind: MasterPlan
+spec:
+  tests:
+  - tcid: TCID-101
+  - name: Install
+`,
+			expectErr: "plan.yml:6: missing tcid in spec.tests[1]",
+		},
+		"unknown field": {
+			data: `
+kind: MasterPlan
+spec:
+  tests:
+  - tcid: TCID-101
+    owner: someone
+`,
+			expectErr: "plan.yml:6: field owner not found",
+		},
+		"invalid labels": {
+			data: `
+kind: MasterPlan
+spec:
+  tests:
+  - tcid: TCID-101
+    labels:
+    - test/group
+`,
+			expectErr: "plan.yml:7: cannot unmarshal",
+		},
+		"invalid yaml": {
+			data: `
+kind: MasterPlan
+spec:
+  tests:
+  - tcid: TCID-101
+   name: Install
+`,
+			expectErr: "plan.yml:3: did not find expected key",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			plan, err := ParseMasterPlan("plan.yml", []byte(mock.data))
+			if mock.expectErr != "" {
+				if err == nil {
+					t.Fatalf("Expected error %q got none", mock.expectErr)
+				}
+				if !strings.Contains(err.Error(), mock.expectErr) {
+					t.Fatalf("Expected error %q got %q", mock.expectErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(plan.Spec.Tests, mock.expectTests) {
+				t.Fatalf(
+					"Expected tests %+v got %+v", mock.expectTests, plan.Spec.Tests,
+				)
+			}
+		})
+	}
+}
+
+func TestLoadMasterPlanSamples(t *testing.T) {
+	var tests = map[string]struct {
+		filename    string
+		expectCount int
+	}{
+		"config testdata": {
+			filename:    "testdata/.master-plan.yml",
+			expectCount: 3,
+		},
+		"deploy testdata": {
+			filename:    "../deploy/testing/.master-plan.yml",
+			expectCount: 126,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			plan, err := LoadMasterPlan(mock.filename)
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if len(plan.Spec.Tests) != mock.expectCount {
+				t.Fatalf(
+					"Expected test count %d got %d",
+					mock.expectCount,
+					len(plan.Spec.Tests),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DesiredSource loads the test cases that are planned
+type DesiredSource interface {
+	LoadDesired() ([]PlannedTest, error)
+}
+
+// ActualSource loads the test cases that are implemented
+type ActualSource interface {
+	LoadActual() ([]ActualTestCase, error)
+}
+
+// DesiredSourceFactory builds a desired source from the given
+// config
+type DesiredSourceFactory func(conf SourceConfig) (DesiredSource, error)
+
+// ActualSourceFactory builds an actual source from the given
+// config
+type ActualSourceFactory func(conf SourceConfig) (ActualSource, error)
+
+// SourceConfig has the details required to build a test source
+type SourceConfig struct {
+	// Format is the name the source's factory is registered with
+	Format string
+
+	// Path is the file, directory or glob pattern that this source
+	// loads its test cases from. It is relative to Files.
+	Path string
+
+	// Optional when set to true ignores this source if its
+	// file(s) are not found
+	Optional bool
+
+	// Files is used to read the file(s) of this source
+	Files FileReader
+
+	// IncludeFiles is used to read the file(s) that are included
+	// by the file(s) of this source. It defaults to Files.
+	IncludeFiles FileReader
+}
+
+// String implements Stringer interface
+func (c SourceConfig) String() string {
+	return c.Format + "=" + c.Path
+}
+
+// matchingFiles returns the file(s) referred to by Path. Path is
+// considered as a glob pattern if it has any wildcard.
+func (c SourceConfig) matchingFiles() ([]string, error) {
+	if !isGlob(c.Path) {
+		return []string{c.Path}, nil
+	}
+	return globFiles(c.Files, c.Path)
+}
+
+// matchingFilesWithSuffix returns the file(s) referred to by Path.
+// Path can be a directory, a file or a glob pattern. All the files
+// found in the directory & its sub directories that end with any of
+// the given suffixes are returned if Path is a directory.
+//
+// NOTE:
+//	Files in vendor, testdata & .git directories are never returned
+// while listing a directory
+func (c SourceConfig) matchingFilesWithSuffix(suffixes ...string) ([]string, error) {
+	if isGlob(c.Path) || hasAnySuffix(c.Path, suffixes) {
+		return c.matchingFiles()
+	}
+	names, err := c.Files.ListFiles(c.Path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, name := range names {
+		if !hasAnySuffix(name, suffixes) || isSkippedFile(name) {
+			continue
+		}
+		files = append(files, name)
+	}
+	return files, nil
+}
+
+// SourceConfigs is a list of source configs that can be set as
+// a command line flag
+//
+// NOTE:
+//	Each flag value is of the form 'format=path' e.g.
+// --actual-source=gitlabci=.gitlab-ci.yml
+type SourceConfigs []SourceConfig
+
+// String implements flag.Value interface
+func (l *SourceConfigs) String() string {
+	var values []string
+	for _, conf := range *l {
+		values = append(values, conf.String())
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements flag.Value interface
+func (l *SourceConfigs) Set(value string) error {
+	words := strings.SplitN(value, "=", 2)
+	if len(words) != 2 || words[0] == "" || words[1] == "" {
+		return errors.Errorf(
+			"Invalid source %q: want format=path", value,
+		)
+	}
+	*l = append(*l, SourceConfig{
+		Format: words[0],
+		Path:   words[1],
+	})
+	return nil
+}
+
+// PathSourceConfigs is a list of source configs of a default format
+// that can be set as a command line flag
+//
+// NOTE:
+//	Each flag value is either a path e.g. --plan=.master-plan.yml or
+// of the form 'format=path' e.g. --ci=githubactions=.github/workflows
+type PathSourceConfigs struct {
+	// Format is used for the values without any format
+	Format string
+
+	SourceConfigs
+}
+
+// Set implements flag.Value interface
+func (l *PathSourceConfigs) Set(value string) error {
+	words := strings.SplitN(value, "=", 2)
+	if len(words) == 2 && isRegisteredFormat(words[0]) {
+		return l.SourceConfigs.Set(value)
+	}
+	if value == "" {
+		return errors.Errorf("Invalid source %q: want path or format=path", value)
+	}
+	l.SourceConfigs = append(l.SourceConfigs, SourceConfig{
+		Format: l.Format,
+		Path:   value,
+	})
+	return nil
+}
+
+var (
+	registryLock sync.RWMutex
+
+	desiredSourceRegistry = map[string]DesiredSourceFactory{}
+	actualSourceRegistry  = map[string]ActualSourceFactory{}
+)
+
+// RegisterDesiredSource registers the factory of desired sources
+// against the given format. A factory registered earlier against
+// the same format is replaced.
+func RegisterDesiredSource(format string, factory DesiredSourceFactory) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	desiredSourceRegistry[format] = factory
+}
+
+// RegisterActualSource registers the factory of actual sources
+// against the given format. A factory registered earlier against
+// the same format is replaced.
+func RegisterActualSource(format string, factory ActualSourceFactory) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	actualSourceRegistry[format] = factory
+}
+
+// NewDesiredSource returns the desired source that is registered
+// against the format of the given config
+func NewDesiredSource(conf SourceConfig) (DesiredSource, error) {
+	registryLock.RLock()
+	factory := desiredSourceRegistry[conf.Format]
+	registryLock.RUnlock()
+	if factory == nil {
+		return nil, errors.Errorf(
+			"Unsupported desired source format %q: want one of %v",
+			conf.Format,
+			DesiredSourceFormats(),
+		)
+	}
+	return factory(conf)
+}
+
+// NewActualSource returns the actual source that is registered
+// against the format of the given config
+func NewActualSource(conf SourceConfig) (ActualSource, error) {
+	registryLock.RLock()
+	factory := actualSourceRegistry[conf.Format]
+	registryLock.RUnlock()
+	if factory == nil {
+		return nil, errors.Errorf(
+			"Unsupported actual source format %q: want one of %v",
+			conf.Format,
+			ActualSourceFormats(),
+		)
+	}
+	return factory(conf)
+}
+
+// isRegisteredFormat returns true if any desired or actual source
+// is registered against the given format
+func isRegisteredFormat(format string) bool {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	return desiredSourceRegistry[format] != nil ||
+		actualSourceRegistry[format] != nil
+}
+
+// DesiredSourceFormats returns the sorted list of registered
+// desired source formats
+func DesiredSourceFormats() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	var formats []string
+	for format := range desiredSourceRegistry {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// ActualSourceFormats returns the sorted list of registered
+// actual source formats
+func ActualSourceFormats() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	var formats []string
+	for format := range actualSourceRegistry {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
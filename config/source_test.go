@@ -0,0 +1,383 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"mayadata.io/e2e-metrics/metrics"
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+)
+
+type fakeActualSource struct {
+	tests []ActualTestCase
+}
+
+func (s *fakeActualSource) LoadActual() ([]ActualTestCase, error) {
+	return s.tests, nil
+}
+
+func TestSourceConfigsSet(t *testing.T) {
+	var tests = map[string]struct {
+		values []string
+		expect SourceConfigs
+		isErr  bool
+	}{
+		"single source": {
+			values: []string{"gitlabci=.gitlab-ci.yml"},
+			expect: SourceConfigs{
+				{Format: "gitlabci", Path: ".gitlab-ci.yml"},
+			},
+		},
+		"multiple sources": {
+			values: []string{"masterplan=a.yml", "masterplan=plans/*.yml"},
+			expect: SourceConfigs{
+				{Format: "masterplan", Path: "a.yml"},
+				{Format: "masterplan", Path: "plans/*.yml"},
+			},
+		},
+		"missing path": {
+			values: []string{"gitlabci="},
+			isErr:  true,
+		},
+		"missing format": {
+			values: []string{".gitlab-ci.yml"},
+			isErr:  true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var got SourceConfigs
+			var err error
+			for _, value := range mock.values {
+				err = got.Set(value)
+				if err != nil {
+					break
+				}
+			}
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !mock.isErr && !reflect.DeepEqual(got, mock.expect) {
+				t.Fatalf("Expected %v got %v", mock.expect, got)
+			}
+		})
+	}
+}
+
+func TestPathSourceConfigsSet(t *testing.T) {
+	var tests = map[string]struct {
+		values []string
+		expect SourceConfigs
+		isErr  bool
+	}{
+		"paths": {
+			values: []string{".gitlab-ci.yml", "ci/*.yml"},
+			expect: SourceConfigs{
+				{Format: "gitlabci", Path: ".gitlab-ci.yml"},
+				{Format: "gitlabci", Path: "ci/*.yml"},
+			},
+		},
+		"paths & formats": {
+			values: []string{".gitlab-ci.yml", "githubactions=.github/workflows"},
+			expect: SourceConfigs{
+				{Format: "gitlabci", Path: ".gitlab-ci.yml"},
+				{Format: "githubactions", Path: ".github/workflows"},
+			},
+		},
+		"path with unregistered format": {
+			values: []string{"a=b.yml"},
+			expect: SourceConfigs{
+				{Format: "gitlabci", Path: "a=b.yml"},
+			},
+		},
+		"empty path": {
+			values: []string{""},
+			isErr:  true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := PathSourceConfigs{Format: FormatGitlabCI}
+			var err error
+			for _, value := range mock.values {
+				err = got.Set(value)
+				if err != nil {
+					break
+				}
+			}
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !mock.isErr && !reflect.DeepEqual(got.SourceConfigs, mock.expect) {
+				t.Fatalf("Expected %v got %v", mock.expect, got.SourceConfigs)
+			}
+		})
+	}
+}
+
+func TestNewSourceUnsupportedFormat(t *testing.T) {
+	_, err := NewDesiredSource(SourceConfig{Format: "unknown"})
+	if err == nil {
+		t.Fatalf("Expected error for unsupported desired format got none")
+	}
+	_, err = NewActualSource(SourceConfig{Format: "unknown"})
+	if err == nil {
+		t.Fatalf("Expected error for unsupported actual format got none")
+	}
+}
+
+func TestConfigLoadMultipleSources(t *testing.T) {
+	RegisterActualSource("fake", func(conf SourceConfig) (ActualSource, error) {
+		return &fakeActualSource{
+			tests: []ActualTestCase{
+				{TCID: "TCID-OPENEBS-UPGRADE", Format: "fake"},
+			},
+		}, nil
+	})
+
+	log := &logstesting.TestLogger{
+		T: t,
+	}
+	config := New(LoadableConfig{
+		Path: "testdata/",
+		Log:  log,
+		Prom: metrics.New(log),
+		DesiredSources: []SourceConfig{
+			{Format: FormatMasterPlan, Path: ".master-plan.yml"},
+			{Format: FormatMasterPlan, Path: "plans/*.yml"},
+		},
+		ActualSources: []SourceConfig{
+			{Format: FormatGitlabCI, Path: ".gitlab-ci.yml"},
+			{Format: "fake", Path: "any"},
+		},
+	})
+	got, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	var gotDesired, gotActual []string
+	for tcid := range got.DesiredTestCases {
+		gotDesired = append(gotDesired, tcid)
+	}
+	for tcid := range got.ActualTestCases {
+		gotActual = append(gotActual, tcid)
+	}
+	sort.Strings(gotDesired)
+	sort.Strings(gotActual)
+	var expectDesired = []string{
+		"TCID-DIR-HEALTH-CHECK",
+		"TCID-DIR-HEALTH-CHECK-V2",
+		"TCID-DIR-INSTALL-ON-LOCAL-PV",
+		"TCID-OPENEBS-UPGRADE",
+	}
+	var expectActual = []string{
+		"TCID-DIR-HEALTH-CHECK",
+		"TCID-DIR-HEALTH-CHECK-V2",
+		"TCID-OPENEBS-UPGRADE",
+	}
+	if !reflect.DeepEqual(gotDesired, expectDesired) {
+		t.Fatalf("Expected desired %v got %v", expectDesired, gotDesired)
+	}
+	if !reflect.DeepEqual(gotActual, expectActual) {
+		t.Fatalf("Expected actual %v got %v", expectActual, gotActual)
+	}
+	if got.ActualTestCases["TCID-OPENEBS-UPGRADE"].Format != "fake" {
+		t.Fatalf(
+			"Expected test case from fake source got %+v",
+			got.ActualTestCases["TCID-OPENEBS-UPGRADE"],
+		)
+	}
+}
+
+func TestConfigLoadMissingSource(t *testing.T) {
+	log := &logstesting.TestLogger{
+		T: t,
+	}
+	config := New(LoadableConfig{
+		Path: "testdata/",
+		Log:  log,
+		Prom: metrics.New(log),
+		ActualSources: []SourceConfig{
+			{Format: FormatGitlabCI, Path: "missing.yml"},
+		},
+	})
+	_, err := config.Load()
+	if err == nil {
+		t.Fatalf("Expected error for missing source got none")
+	}
+}
+
+func TestConfigLoadMapReader(t *testing.T) {
+	files := NewMapReader(map[string]string{
+		"plan.yml": `
+kind: MasterPlan
+spec:
+  tests:
+  - tcid: TCID-OPENEBS-UPGRADE
+  - tcid: TCID-OPENEBS-BACKUP
+`,
+		"ci.yml": `
+TCID-OPENEBS-UPGRADE:
+  script:
+  - ./upgrade
+`,
+	})
+	log := &logstesting.TestLogger{
+		T: t,
+	}
+	config := New(LoadableConfig{
+		Log:  log,
+		Prom: metrics.New(log),
+		DesiredSources: []SourceConfig{
+			{Format: FormatMasterPlan, Path: "plan*.yml", Files: files},
+		},
+		ActualSources: []SourceConfig{
+			{Format: FormatGitlabCI, Path: "ci.yml", Files: files},
+			{Format: FormatGitlabCI, Path: "missing.yml", Files: files, Optional: true},
+		},
+	})
+	got, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if len(got.DesiredTestCases) != 2 {
+		t.Fatalf("Expected 2 desired got %+v", got.DesiredTestCases)
+	}
+	if _, found := got.ActualTestCases["TCID-OPENEBS-UPGRADE"]; !found ||
+		len(got.ActualTestCases) != 1 {
+		t.Fatalf("Expected actual TCID-OPENEBS-UPGRADE got %+v", got.ActualTestCases)
+	}
+}
+
+func TestConfigLoadDuplicates(t *testing.T) {
+	files := NewMapReader(map[string]string{
+		"plan.yml": `
+kind: MasterPlan
+spec:
+  tests:
+  - tcid: TCID-OPENEBS-UPGRADE
+  - tcid: TCID-OPENEBS-BACKUP
+  - tcid: TCID-OPENEBS-UPGRADE
+`,
+		"ci.yml": `
+TCID-OPENEBS-BACKUP:
+  script:
+  - ./backup
+`,
+		"more-ci.yml": `
+TCID-OPENEBS-BACKUP:
+  script:
+  - ./backup
+`,
+	})
+	var tests = map[string]struct {
+		actualSources    []SourceConfig
+		expectDuplicates []string
+	}{
+		"same job loaded twice": {
+			actualSources: []SourceConfig{
+				{Format: FormatGitlabCI, Path: "ci.yml", Files: files},
+				{Format: FormatGitlabCI, Path: "ci.yml", Files: files},
+			},
+			expectDuplicates: []string{"TCID-OPENEBS-UPGRADE"},
+		},
+		"jobs of different files": {
+			actualSources: []SourceConfig{
+				{Format: FormatGitlabCI, Path: "ci.yml", Files: files},
+				{Format: FormatGitlabCI, Path: "more-ci.yml", Files: files},
+			},
+			expectDuplicates: []string{"TCID-OPENEBS-BACKUP", "TCID-OPENEBS-UPGRADE"},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			log := &logstesting.TestLogger{
+				T: t,
+			}
+			config := New(LoadableConfig{
+				Log:  log,
+				Prom: metrics.New(log),
+				DesiredSources: []SourceConfig{
+					{Format: FormatMasterPlan, Path: "plan.yml", Files: files},
+				},
+				ActualSources: mock.actualSources,
+			})
+			got, err := config.Load()
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(got.DuplicateTestCases, mock.expectDuplicates) {
+				t.Fatalf(
+					"Expected duplicates %v got %v",
+					mock.expectDuplicates,
+					got.DuplicateTestCases,
+				)
+			}
+		})
+	}
+}
+
+func TestConfigLoadDeprecatedOnce(t *testing.T) {
+	files := NewMapReader(map[string]string{
+		"ci.yml": `
+tcid-openebs-upgrade:
+  script:
+  - ./upgrade
+`,
+		"more-ci.yml": `
+tcid-openebs-upgrade:
+  script:
+  - ./upgrade
+`,
+	})
+	log := &logstesting.TestLogger{
+		T: t,
+	}
+	config := New(LoadableConfig{
+		Log:  log,
+		Prom: metrics.New(log),
+		ActualSources: []SourceConfig{
+			{Format: FormatGitlabCI, Path: "ci.yml", Files: files},
+			{Format: FormatGitlabCI, Path: "more-ci.yml", Files: files},
+		},
+	})
+	got, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	var expectDeprecated = []string{"tcid-openebs-upgrade"}
+	if !reflect.DeepEqual(got.DeprecatedTCIDs(), expectDeprecated) {
+		t.Fatalf(
+			"Expected deprecated %v got %v", expectDeprecated, got.DeprecatedTCIDs(),
+		)
+	}
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	prom "mayadata.io/e2e-metrics/metrics"
+)
+
+const (
+	// DefaultDimensionLabelKeyPrefix is prefixed to the name of a
+	// dimension to get the label of planned tests that has the
+	// value of this dimension e.g. test/component
+	DefaultDimensionLabelKeyPrefix string = "test/"
+)
+
+// dimensionNameRegex matches the dimension names that are valid
+// prometheus label names
+var dimensionNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Taxonomy has the dimensions that the test count metrics are
+// split by & the values that are allowed for these dimensions
+//
+// NOTE:
+//	A sample taxonomy looks like below:
+//
+//	dimensions:
+//	- name: component
+//	  values: [director, dao, openebs]
+//	- name: feature
+//	  labelKey: team/feature
+//	  values: [dmaas, auth, teaming]
+//	implementationTypes: [litmus, dope]
+type Taxonomy struct {
+	Dimensions []Dimension `yaml:"dimensions"`
+
+	// ImplementationTypes that tests are allowed to be implemented
+	// with. Any type is allowed if these are not set.
+	ImplementationTypes []prom.TestImplementationType `yaml:"implementationTypes"`
+}
+
+// Dimension is a label of test count metrics whose value is read
+// from a label of each planned test
+type Dimension struct {
+	Name string `yaml:"name"`
+
+	// LabelKey is the label of planned tests that has the value of
+	// this dimension. It defaults to the name prefixed with test/
+	LabelKey string `yaml:"labelKey"`
+
+	// Values that are allowed for this dimension. Any value is
+	// allowed if these are not set.
+	Values []string `yaml:"values"`
+}
+
+// UndeclaredValue is a value of a test case that is not declared
+// in the taxonomy
+type UndeclaredValue struct {
+	TCID      string
+	Dimension string
+	Value     string
+}
+
+// String implements Stringer interface
+func (u UndeclaredValue) String() string {
+	return fmt.Sprintf("%s has undeclared %s %q", u.TCID, u.Dimension, u.Value)
+}
+
+// DefaultTaxonomy returns the taxonomy that is used when none is
+// configured. It allows any value for each of the default test count
+// dimensions.
+func DefaultTaxonomy() *Taxonomy {
+	var taxonomy Taxonomy
+	for _, name := range prom.DefaultTestCountDimensions {
+		taxonomy.Dimensions = append(taxonomy.Dimensions, Dimension{
+			Name:     name,
+			LabelKey: DefaultDimensionLabelKeyPrefix + name,
+		})
+	}
+	return &taxonomy
+}
+
+// LoadTaxonomy reads the given file & returns the taxonomy found in
+// this file
+func LoadTaxonomy(filename string) (*Taxonomy, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTaxonomy(filename, data)
+}
+
+// ParseTaxonomy decodes the given data into a taxonomy. Labels keys
+// of dimensions are defaulted. Invalid data results in error that
+// points to the offending line of the given file.
+func ParseTaxonomy(filename string, data []byte) (*Taxonomy, error) {
+	var taxonomy Taxonomy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	// unknown fields are treated as errors
+	decoder.KnownFields(true)
+	err := decoder.Decode(&taxonomy)
+	if err == io.EOF {
+		return nil, newFileError(filename, 0, "no taxonomy found")
+	}
+	if err != nil {
+		return nil, wrapYAMLError(filename, err)
+	}
+
+	// decode once again to get hold of line numbers
+	var doc yaml.Node
+	err = yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, wrapYAMLError(filename, err)
+	}
+	dimensions := mappingValue(documentRoot(&doc), "dimensions")
+
+	var errs FileErrors
+	if len(taxonomy.Dimensions) == 0 {
+		errs = append(errs, newFileError(filename, 0, "no dimensions found"))
+	}
+	var names = map[string]bool{}
+	for i := range taxonomy.Dimensions {
+		var line int
+		if dimensions != nil && i < len(dimensions.Content) {
+			line = dimensions.Content[i].Line
+		}
+		dimension := &taxonomy.Dimensions[i]
+		switch {
+		case !dimensionNameRegex.MatchString(dimension.Name):
+			errs = append(
+				errs,
+				newFileError(
+					filename, line, "invalid name %q in dimensions[%d]", dimension.Name, i,
+				),
+			)
+		case prom.IsReservedTestCountLblName(dimension.Name):
+			errs = append(
+				errs,
+				newFileError(
+					filename, line, "reserved name %q in dimensions[%d]", dimension.Name, i,
+				),
+			)
+		case names[dimension.Name]:
+			errs = append(
+				errs,
+				newFileError(
+					filename, line, "duplicate name %q in dimensions[%d]", dimension.Name, i,
+				),
+			)
+		}
+		names[dimension.Name] = true
+		if dimension.LabelKey == "" {
+			dimension.LabelKey = DefaultDimensionLabelKeyPrefix + dimension.Name
+		}
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return &taxonomy, nil
+}
+
+// DimensionNames returns the names of the dimensions of this
+// taxonomy
+func (t *Taxonomy) DimensionNames() []string {
+	var names []string
+	for _, dimension := range t.Dimensions {
+		names = append(names, dimension.Name)
+	}
+	return names
+}
+
+// DimensionValues returns the value of each dimension of the given
+// planned test based on its labels
+func (t *Taxonomy) DimensionValues(test PlannedTest) map[string]string {
+	var values = map[string]string{}
+	for _, dimension := range t.Dimensions {
+		values[dimension.Name] = test.Labels[dimension.LabelKey]
+	}
+	return values
+}
+
+// isAllowed returns true if the given value is found in the given
+// allowed values or if there are no allowed values
+func isAllowed(allowed []string, value string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == value {
+			return true
+		}
+	}
+	return false
+}
+
+// UndeclaredValues returns the dimension values & implementation
+// types of the given test cases that are not declared in this
+// taxonomy
+//
+// NOTE:
+//	Planned tests without the label of a dimension are not flagged
+func (t *Taxonomy) UndeclaredValues(tcm *TestCasesMetrics) []UndeclaredValue {
+	var undeclared []UndeclaredValue
+	for _, test := range tcm.DesiredTestCases {
+		for _, dimension := range t.Dimensions {
+			value := test.Labels[dimension.LabelKey]
+			if value == "" || isAllowed(dimension.Values, value) {
+				continue
+			}
+			undeclared = append(undeclared, UndeclaredValue{
+				TCID:      test.TCID,
+				Dimension: dimension.Name,
+				Value:     value,
+			})
+		}
+	}
+	var implTypes []string
+	for _, implType := range t.ImplementationTypes {
+		implTypes = append(implTypes, string(implType))
+	}
+	for _, test := range tcm.ActualTestCases {
+		if isAllowed(implTypes, string(test.ImplementationType)) {
+			continue
+		}
+		undeclared = append(undeclared, UndeclaredValue{
+			TCID:      test.TCID,
+			Dimension: prom.TestImplementationTypeLblName,
+			Value:     string(test.ImplementationType),
+		})
+	}
+	sort.Slice(undeclared, func(i, j int) bool {
+		return undeclared[i].String() < undeclared[j].String()
+	})
+	return undeclared
+}
+
+// countKey returns the key of the given dimension values &
+// implementation type that is used to count tests per combination
+func (t *Taxonomy) countKey(values map[string]string, implType prom.TestImplementationType) string {
+	var parts []string
+	for _, dimension := range t.Dimensions {
+		parts = append(parts, values[dimension.Name])
+	}
+	parts = append(parts, string(implType))
+	// parts are quoted to keep the keys of different values apart
+	return fmt.Sprintf("%q", parts)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"mayadata.io/e2e-metrics/metrics"
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+)
+
+func TestParseTaxonomy(t *testing.T) {
+	var tests = map[string]struct {
+		data           string
+		expectTaxonomy *Taxonomy
+		expectErr      string
+	}{
+		"all fields": {
+			data: `
+dimensions:
+- name: component
+  values: [director, dao]
+- name: team
+  labelKey: owner/team
+implementationTypes: [litmus]
+`,
+			expectTaxonomy: &Taxonomy{
+				Dimensions: []Dimension{
+					{
+						Name:     "component",
+						LabelKey: "test/component",
+						Values:   []string{"director", "dao"},
+					},
+					{
+						Name:     "team",
+						LabelKey: "owner/team",
+					},
+				},
+				ImplementationTypes: []metrics.TestImplementationType{
+					metrics.TestImplementationTypeLitmus,
+				},
+			},
+		},
+		"empty file": {
+			data:      ``,
+			expectErr: "taxonomy.yml: no taxonomy found",
+		},
+		"no dimensions": {
+			data: `
+implementationTypes: [litmus]
+`,
+			expectErr: "taxonomy.yml: no dimensions found",
+		},
+		"invalid name": {
+			data: `
+dimensions:
+- name: component
+- name: test/group
+`,
+			expectErr: `taxonomy.yml:4: invalid name "test/group" in dimensions[1]`,
+		},
+		"reserved name": {
+			data: `
+dimensions:
+- name: testimpltype
+`,
+			expectErr: `taxonomy.yml:3: reserved name "testimpltype" in dimensions[0]`,
+		},
+		"reserved pipelinecoverage name": {
+			data: `
+dimensions:
+- name: pipelinecoverage
+`,
+			expectErr: `taxonomy.yml:3: reserved name "pipelinecoverage" in dimensions[0]`,
+		},
+		"duplicate name": {
+			data: `
+dimensions:
+- name: component
+- name: component
+  labelKey: team/component
+`,
+			expectErr: `taxonomy.yml:4: duplicate name "component" in dimensions[1]`,
+		},
+		"unknown field": {
+			data: `
+dimensions:
+- name: component
+  allowed: [director]
+`,
+			expectErr: "taxonomy.yml:4: field allowed not found",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			taxonomy, err := ParseTaxonomy("taxonomy.yml", []byte(mock.data))
+			if mock.expectErr != "" {
+				if err == nil {
+					t.Fatalf("Expected error %q got none", mock.expectErr)
+				}
+				if !strings.Contains(err.Error(), mock.expectErr) {
+					t.Fatalf("Expected error %q got %q", mock.expectErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(taxonomy, mock.expectTaxonomy) {
+				t.Fatalf("Expected no diff got\n%s", cmp.Diff(mock.expectTaxonomy, taxonomy))
+			}
+		})
+	}
+}
+
+func TestConfigLoadUndeclaredValues(t *testing.T) {
+	var tests = map[string]struct {
+		taxonomyFile string
+		expect       []UndeclaredValue
+	}{
+		"default taxonomy allows any value": {},
+		"taxonomy with allowed values": {
+			taxonomyFile: "testdata/labels/taxonomy.yml",
+			expect: []UndeclaredValue{
+				{TCID: "TCID-AUTH-GOOGLE", Dimension: "testimpltype", Value: "litmus"},
+				{TCID: "TCID-AUTH-LOCAL", Dimension: "kind", Value: "localauth"},
+				{TCID: "TCID-AUTH-LOCAL", Dimension: "testimpltype", Value: "litmus"},
+				{TCID: "TCID-DMAAS-BACKUP", Dimension: "testimpltype", Value: "litmus"},
+				{TCID: "TCID-NOT-PLANNED", Dimension: "testimpltype", Value: "litmus"},
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var taxonomy *Taxonomy
+			if mock.taxonomyFile != "" {
+				var err error
+				taxonomy, err = LoadTaxonomy(mock.taxonomyFile)
+				if err != nil {
+					t.Fatalf("Expected no error got %v", err)
+				}
+			}
+			log := &logstesting.TestLogger{T: t}
+			config := New(LoadableConfig{
+				Path:     "testdata/labels",
+				Log:      log,
+				Prom:     metrics.New(log),
+				Taxonomy: taxonomy,
+			})
+			got, err := config.Load()
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(got.UndeclaredValues, mock.expect) {
+				t.Fatalf("Expected no diff got\n%s", cmp.Diff(mock.expect, got.UndeclaredValues))
+			}
+		})
+	}
+}
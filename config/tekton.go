@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FormatTekton is the source format of tekton pipeline & task
+	// files
+	FormatTekton string = "tekton"
+
+	// KindTektonPipeline is the kind of tekton pipeline resource
+	KindTektonPipeline string = "Pipeline"
+
+	// KindTektonTask is the kind of tekton task resource
+	KindTektonTask string = "Task"
+
+	// KindTektonClusterTask is the kind of tekton cluster task
+	// resource
+	KindTektonClusterTask string = "ClusterTask"
+)
+
+func init() {
+	RegisterActualSource(FormatTekton, NewTektonSource)
+}
+
+// TektonPipeline is the typed representation of tekton pipeline
+//
+// NOTE:
+//	A sample pipeline looks like below:
+//
+//	apiVersion: tekton.dev/v1beta1
+//	kind: Pipeline
+//	metadata:
+//	  name: e2e
+//	spec:
+//	  tasks:
+//	  - name: TCID-DIR-HEALTH-CHECK
+//	    taskRef:
+//	      name: director-health-check
+//	  - name: upgrade
+//	    taskSpec:
+//	      metadata:
+//	        labels:
+//	          e2e.mayadata.io/tcid: TCID-OPENEBS-UPGRADE
+type TektonPipeline struct {
+	Metadata KubeObjectMeta     `yaml:"metadata"`
+	Spec     TektonPipelineSpec `yaml:"spec"`
+}
+
+// TektonPipelineSpec has the tasks of a tekton pipeline
+type TektonPipelineSpec struct {
+	Tasks   []TektonPipelineTask `yaml:"tasks"`
+	Finally []TektonPipelineTask `yaml:"finally"`
+}
+
+// TektonPipelineTask is one of the tasks of a tekton pipeline
+type TektonPipelineTask struct {
+	// Line is the line where this task is declared
+	Line int `yaml:"-"`
+
+	Name     string              `yaml:"name"`
+	TaskRef  *TektonTaskRef      `yaml:"taskRef"`
+	TaskSpec *TektonEmbeddedTask `yaml:"taskSpec"`
+	RunAfter StringList          `yaml:"runAfter"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface
+func (t *TektonPipelineTask) UnmarshalYAML(node *yaml.Node) error {
+	type plain TektonPipelineTask
+	err := node.Decode((*plain)(t))
+	if err != nil {
+		return err
+	}
+	t.Line = node.Line
+	return nil
+}
+
+// TektonTaskRef refers to the task that is run by a pipeline task
+type TektonTaskRef struct {
+	Name string `yaml:"name"`
+	Kind string `yaml:"kind"`
+}
+
+// TektonEmbeddedTask is a task that is declared within a pipeline
+type TektonEmbeddedTask struct {
+	Metadata KubeObjectMeta `yaml:"metadata"`
+}
+
+// TektonTask is the typed representation of tekton task & cluster
+// task
+type TektonTask struct {
+	Metadata KubeObjectMeta `yaml:"metadata"`
+}
+
+// TektonSource loads the actual test cases from tekton pipelines &
+// tasks
+type TektonSource struct {
+	SourceConfig
+}
+
+// NewTektonSource returns a new instance of TektonSource
+func NewTektonSource(conf SourceConfig) (ActualSource, error) {
+	return &TektonSource{
+		SourceConfig: conf,
+	}, nil
+}
+
+// LoadActual implements ActualSource interface
+//
+// NOTE:
+//	Tasks as well as pipeline tasks that are either named with the
+// test case id or labelled with TestCaseIDLabelKey are considered
+// as test cases. A pipeline task is also labelled if the task it
+// refers to is labelled. Such a pipeline task is not considered
+// since the task it refers to is the test case. Resources of other
+// kinds are ignored.
+func (s *TektonSource) LoadActual() ([]ActualTestCase, error) {
+	resources, err := loadKubeResources(s.SourceConfig)
+	if err != nil {
+		return nil, err
+	}
+	// labels of tasks are looked up by pipeline tasks that refer
+	// to them
+	var taskLabels = map[string]map[string]string{}
+	// test case ids of tasks that are test cases
+	var taskTCIDs = map[string]string{}
+	var tests []ActualTestCase
+	for _, resource := range resources {
+		if resource.Kind != KindTektonTask && resource.Kind != KindTektonClusterTask {
+			continue
+		}
+		var task TektonTask
+		err = resource.decode(&task)
+		if err != nil {
+			return nil, err
+		}
+		taskLabels[resource.Kind+"/"+task.Metadata.Name] = task.Metadata.Labels
+		test, ok := newLabelledActualTestCase(task.Metadata.Name, task.Metadata.Labels)
+		if !ok {
+			continue
+		}
+		test.Format = FormatTekton
+		test.File = resource.File
+		test.Line = resource.node.Line
+		test.Resource = resource.String()
+		taskTCIDs[resource.Kind+"/"+task.Metadata.Name] = test.TCID
+		tests = append(tests, test)
+	}
+	for _, resource := range resources {
+		if resource.Kind != KindTektonPipeline {
+			continue
+		}
+		var pipeline TektonPipeline
+		err = resource.decode(&pipeline)
+		if err != nil {
+			return nil, err
+		}
+		pipelineTasks := append(pipeline.Spec.Tasks, pipeline.Spec.Finally...)
+		for _, pipelineTask := range pipelineTasks {
+			var labels map[string]string
+			var ref string
+			switch {
+			case pipelineTask.TaskSpec != nil:
+				labels = pipelineTask.TaskSpec.Metadata.Labels
+			case pipelineTask.TaskRef != nil:
+				kind := pipelineTask.TaskRef.Kind
+				if kind == "" {
+					kind = KindTektonTask
+				}
+				ref = kind + "/" + pipelineTask.TaskRef.Name
+				labels = taskLabels[ref]
+			}
+			test, ok := newLabelledActualTestCase(pipelineTask.Name, labels)
+			if !ok {
+				continue
+			}
+			if tcid, found := taskTCIDs[ref]; found && tcid == test.TCID {
+				// referred task is already registered
+				continue
+			}
+			test.Format = FormatTekton
+			test.File = resource.File
+			test.Line = pipelineTask.Line
+			test.Resource = resource.String()
+			tests = append(tests, test)
+		}
+	}
+	return tests, nil
+}
@@ -0,0 +1,8 @@
+package e2e
+
+import "testing"
+
+func TestUpgrade(t *testing.T) {
+	t.Run("TCID-OPENEBS-UPGRADE-CSTOR", func(t *testing.T) {})
+	t.Run("tcid-openebs-upgrade-jiva", func(t *testing.T) {})
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlLineErrRegex matches the line number that is embedded in
+// error messages returned by the yaml library
+//
+// NOTE:
+//	Syntax errors look like 'yaml: line 3: did not find expected key'
+// while type errors look like 'line 7: field foo not found in type x'
+var yamlLineErrRegex = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// FileError is an error found in a config file. It points to
+// the line that was found to be invalid if this line is known.
+type FileError struct {
+	File string
+	Line int
+	Msg  string
+}
+
+// Error implements error interface
+func (e *FileError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s", e.File, e.Msg)
+}
+
+// FileErrors is a list of errors found in one or more config
+// files
+type FileErrors []*FileError
+
+// Error implements error interface
+func (e FileErrors) Error() string {
+	var msgs []string
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// newFileError returns a new instance of FileError
+func newFileError(file string, line int, format string, args ...interface{}) *FileError {
+	return &FileError{
+		File: file,
+		Line: line,
+		Msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+// toFileError converts the given yaml error message into a
+// FileError
+func toFileError(file, msg string) *FileError {
+	matches := yamlLineErrRegex.FindStringSubmatch(msg)
+	if len(matches) != 3 {
+		return &FileError{
+			File: file,
+			Msg:  strings.TrimPrefix(msg, "yaml: "),
+		}
+	}
+	// error is ignored since regex ensures digits
+	line, _ := strconv.Atoi(matches[1])
+	return &FileError{
+		File: file,
+		Line: line,
+		Msg:  matches[2],
+	}
+}
+
+// wrapYAMLError converts the error returned by the yaml library
+// into error(s) that point to the file & line that was found to
+// be invalid
+func wrapYAMLError(file string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		var errs FileErrors
+		for _, msg := range typeErr.Errors {
+			errs = append(errs, toFileError(file, msg))
+		}
+		return errs
+	}
+	return toFileError(file, err.Error())
+}
+
+// appendYAMLError appends the error returned by the yaml library
+// to the given list of file errors
+func appendYAMLError(errs FileErrors, file string, err error) FileErrors {
+	switch wrapped := wrapYAMLError(file, err).(type) {
+	case FileErrors:
+		return append(errs, wrapped...)
+	case *FileError:
+		return append(errs, wrapped)
+	}
+	return errs
+}
+
+// documentRoot returns the top level node of the given yaml
+// document node
+func documentRoot(node *yaml.Node) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return node.Content[0]
+	}
+	return node
+}
+
+// mappingValue returns the value node corresponding to the given
+// key if the provided node is a yaml mapping
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	_, value := mappingEntry(node, key)
+	return value
+}
+
+// mappingEntry returns the key node & value node corresponding to
+// the given key if the provided node is a yaml mapping
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	// mapping node has its keys & values placed one after the other
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// mappingPairs returns the key & value nodes of the given mapping
+// node. Entries of merge keys i.e. '<<: *anchor' are included
+// unless these keys are set explicitly in the mapping.
+func mappingPairs(node *yaml.Node) [][2]*yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var explicit = map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if !isMergeKey(node.Content[i]) {
+			explicit[node.Content[i].Value] = true
+		}
+	}
+	var pairs [][2]*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !isMergeKey(key) {
+			pairs = append(pairs, [2]*yaml.Node{key, value})
+			continue
+		}
+		var merges = []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			merges = value.Content
+		}
+		for _, merge := range merges {
+			for _, pair := range mappingPairs(resolveAlias(merge)) {
+				if explicit[pair[0].Value] {
+					continue
+				}
+				explicit[pair[0].Value] = true
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+	return pairs
+}
+
+// resolveAlias returns the anchored node if the given node is an
+// alias & the given node otherwise
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	if node != nil && node.Kind == yaml.AliasNode && node.Alias != nil {
+		return node.Alias
+	}
+	return node
+}
+
+// isMergeKey returns true if the given node is the yaml merge key
+func isMergeKey(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode &&
+		node.Value == "<<" &&
+		(node.Tag == "" || node.Tag == "!!merge")
+}
+
+// toString returns the string representation of the given value
+func toString(value interface{}) string {
+	return fmt.Sprintf("%v", value)
+}
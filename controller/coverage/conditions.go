@@ -0,0 +1,230 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coverage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"mayadata.io/e2e-metrics/types"
+)
+
+// Reasons of the conditions of PipelineCoverage
+const (
+	ReasonConfigLoaded      string = "ConfigLoaded"
+	ReasonConfigLoadFailed  string = "ConfigLoadFailed"
+	ReasonConfigNotLoaded   string = "ConfigNotLoaded"
+	ReasonTestsPlanned      string = "TestsPlanned"
+	ReasonNoPlannedTests    string = "NoPlannedTests"
+	ReasonInvalidSpec       string = "InvalidSpec"
+	ReasonMinCoverageMet    string = "MinCoverageMet"
+	ReasonBelowMinCoverage  string = "BelowMinCoverage"
+	ReasonAllTestsPlanned   string = "AllTestsPlanned"
+	ReasonInvalidTestsFound string = "InvalidTestsFound"
+)
+
+// newCondition returns a condition of the given type & status
+func newCondition(
+	conditionType types.PipelineCoverageConditionType,
+	status types.ConditionStatus,
+	reason string,
+	message string,
+) types.PipelineCoverageCondition {
+	return types.PipelineCoverageCondition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// getConditions returns the conditions of the calculated coverage
+//
+// NOTE:
+//	Last transition time of a condition is retained from the
+// observed PipelineCoverage if its status did not change
+func (r *Reconciler) getConditions() []types.PipelineCoverageCondition {
+	var conditions []types.PipelineCoverageCondition
+	if r.err != nil {
+		conditions = append(
+			conditions,
+			newCondition(
+				types.PipelineCoverageConfigLoaded,
+				types.ConditionFalse,
+				ReasonConfigLoadFailed,
+				r.err.Error(),
+			),
+		)
+		remaining := []types.PipelineCoverageConditionType{
+			types.PipelineCoveragePlanValid,
+			types.PipelineCoverageCoverageMet,
+			types.PipelineCoverageNoInvalidTests,
+		}
+		if _, ok := errors.Cause(r.err).(*SpecError); ok {
+			// plan can not be read from an invalid path
+			conditions = append(
+				conditions,
+				newCondition(
+					types.PipelineCoveragePlanValid,
+					types.ConditionFalse,
+					ReasonInvalidSpec,
+					r.err.Error(),
+				),
+			)
+			remaining = remaining[1:]
+		}
+		// remaining conditions can not be evaluated
+		for _, conditionType := range remaining {
+			conditions = append(
+				conditions,
+				newCondition(
+					conditionType,
+					types.ConditionUnknown,
+					ReasonConfigNotLoaded,
+					"Config could not be loaded",
+				),
+			)
+		}
+		return r.withTransitionTimes(conditions)
+	}
+
+	conditions = append(
+		conditions,
+		newCondition(
+			types.PipelineCoverageConfigLoaded,
+			types.ConditionTrue,
+			ReasonConfigLoaded,
+			fmt.Sprintf(
+				"Loaded %d planned & %d implemented tests",
+				len(r.metrics.DesiredTestCases),
+				len(r.metrics.ActualTestCases),
+			),
+		),
+		r.getPlanValidCondition(),
+		r.getCoverageMetCondition(),
+		r.getNoInvalidTestsCondition(),
+	)
+	return r.withTransitionTimes(conditions)
+}
+
+// getPlanValidCondition returns the PlanValid condition
+func (r *Reconciler) getPlanValidCondition() types.PipelineCoverageCondition {
+	count := len(r.metrics.DesiredTestCases)
+	if count == 0 {
+		return newCondition(
+			types.PipelineCoveragePlanValid,
+			types.ConditionFalse,
+			ReasonNoPlannedTests,
+			"No tests are planned",
+		)
+	}
+	return newCondition(
+		types.PipelineCoveragePlanValid,
+		types.ConditionTrue,
+		ReasonTestsPlanned,
+		fmt.Sprintf("%d tests are planned", count),
+	)
+}
+
+// getCoverageMetCondition returns the CoverageMet condition based
+// on the minimum coverage of the observed PipelineCoverage
+func (r *Reconciler) getCoverageMetCondition() types.PipelineCoverageCondition {
+	var minCoverage int64
+	if r.observed != nil {
+		minCoverage = r.observed.Spec.MinCoverage
+	}
+	policy := Policy{
+		MinCoverage:     float64(minCoverage),
+		MaxInvalidTests: -1,
+	}
+	violations := policy.Check(r.getSummary())
+	if len(violations) != 0 {
+		return newCondition(
+			types.PipelineCoverageCoverageMet,
+			types.ConditionFalse,
+			ReasonBelowMinCoverage,
+			violations[0].Message,
+		)
+	}
+	return newCondition(
+		types.PipelineCoverageCoverageMet,
+		types.ConditionTrue,
+		ReasonMinCoverageMet,
+		fmt.Sprintf(
+			"Coverage %s is at least %d%%",
+			Percentage(r.coverage),
+			minCoverage,
+		),
+	)
+}
+
+// getNoInvalidTestsCondition returns the NoInvalidTests condition
+func (r *Reconciler) getNoInvalidTestsCondition() types.PipelineCoverageCondition {
+	if len(r.invalidTests) == 0 {
+		return newCondition(
+			types.PipelineCoverageNoInvalidTests,
+			types.ConditionTrue,
+			ReasonAllTestsPlanned,
+			"Every implemented test is planned",
+		)
+	}
+	return newCondition(
+		types.PipelineCoverageNoInvalidTests,
+		types.ConditionFalse,
+		ReasonInvalidTestsFound,
+		r.getInvalidTestsMessage(),
+	)
+}
+
+// getInvalidTestsMessage returns a message with at most
+// MaxResultTestNames invalid tests
+func (r *Reconciler) getInvalidTestsMessage() string {
+	return fmt.Sprintf(
+		"%d tests are implemented but not planned [%s]",
+		len(r.invalidTests),
+		strings.Join(r.getCappedInvalidTests(), ", "),
+	)
+}
+
+// withTransitionTimes sets the last transition time & the observed
+// generation of the given conditions
+func (r *Reconciler) withTransitionTimes(
+	conditions []types.PipelineCoverageCondition,
+) []types.PipelineCoverageCondition {
+	var observed = map[types.PipelineCoverageConditionType]types.PipelineCoverageCondition{}
+	var generation int64
+	if r.observed != nil {
+		generation = r.observed.GetGeneration()
+		for _, condition := range r.observed.Result.Conditions {
+			observed[condition.Type] = condition
+		}
+	}
+	now := metav1.NewTime(r.now().UTC().Truncate(time.Second))
+	for i := range conditions {
+		conditions[i].ObservedGeneration = generation
+		conditions[i].LastTransitionTime = now
+		old, found := observed[conditions[i].Type]
+		if found && old.Status == conditions[i].Status {
+			conditions[i].LastTransitionTime = old.LastTransitionTime
+		}
+	}
+	return conditions
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coverage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"mayadata.io/e2e-metrics/config"
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+	"mayadata.io/e2e-metrics/types"
+)
+
+func TestReconcilerGetConditions(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(time.Date(2020, 5, 2, 10, 0, 0, 0, time.UTC))
+	plannedTwo := &config.TestCasesMetrics{
+		DesiredTestCases: map[string]config.PlannedTest{
+			"TCID-A": {TCID: "TCID-A"},
+			"TCID-B": {TCID: "TCID-B"},
+		},
+		ActualTestCases: map[string]config.ActualTestCase{
+			"TCID-A": {TCID: "TCID-A"},
+			"TCID-X": {TCID: "TCID-X"},
+		},
+	}
+
+	var tests = map[string]struct {
+		observed     *types.PipelineCoverage
+		metrics      *config.TestCasesMetrics
+		validTests   []string
+		invalidTests []string
+		coverage     float32
+		err          error
+		expect       []types.PipelineCoverageCondition
+	}{
+		"config load failed": {
+			metrics: &config.TestCasesMetrics{},
+			err:     errors.New("open .master-plan.yml: no such file or directory"),
+			expect: []types.PipelineCoverageCondition{
+				{
+					Type:               types.PipelineCoverageConfigLoaded,
+					Status:             types.ConditionFalse,
+					Reason:             ReasonConfigLoadFailed,
+					Message:            "open .master-plan.yml: no such file or directory",
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoveragePlanValid,
+					Status:             types.ConditionUnknown,
+					Reason:             ReasonConfigNotLoaded,
+					Message:            "Config could not be loaded",
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoverageCoverageMet,
+					Status:             types.ConditionUnknown,
+					Reason:             ReasonConfigNotLoaded,
+					Message:            "Config could not be loaded",
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoverageNoInvalidTests,
+					Status:             types.ConditionUnknown,
+					Reason:             ReasonConfigNotLoaded,
+					Message:            "Config could not be loaded",
+					LastTransitionTime: now,
+				},
+			},
+		},
+		"invalid spec": {
+			metrics: &config.TestCasesMetrics{},
+			err:     &SpecError{Field: "spec.git.path", Msg: `Path "../repo" is outside the config directory`},
+			expect: []types.PipelineCoverageCondition{
+				{
+					Type:               types.PipelineCoverageConfigLoaded,
+					Status:             types.ConditionFalse,
+					Reason:             ReasonConfigLoadFailed,
+					Message:            `Invalid spec.git.path: Path "../repo" is outside the config directory`,
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoveragePlanValid,
+					Status:             types.ConditionFalse,
+					Reason:             ReasonInvalidSpec,
+					Message:            `Invalid spec.git.path: Path "../repo" is outside the config directory`,
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoverageCoverageMet,
+					Status:             types.ConditionUnknown,
+					Reason:             ReasonConfigNotLoaded,
+					Message:            "Config could not be loaded",
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoverageNoInvalidTests,
+					Status:             types.ConditionUnknown,
+					Reason:             ReasonConfigNotLoaded,
+					Message:            "Config could not be loaded",
+					LastTransitionTime: now,
+				},
+			},
+		},
+		"no planned tests": {
+			metrics: &config.TestCasesMetrics{},
+			expect: []types.PipelineCoverageCondition{
+				{
+					Type:               types.PipelineCoverageConfigLoaded,
+					Status:             types.ConditionTrue,
+					Reason:             ReasonConfigLoaded,
+					Message:            "Loaded 0 planned & 0 implemented tests",
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoveragePlanValid,
+					Status:             types.ConditionFalse,
+					Reason:             ReasonNoPlannedTests,
+					Message:            "No tests are planned",
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoverageCoverageMet,
+					Status:             types.ConditionTrue,
+					Reason:             ReasonMinCoverageMet,
+					Message:            "Coverage 0% is at least 0%",
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoverageNoInvalidTests,
+					Status:             types.ConditionTrue,
+					Reason:             ReasonAllTestsPlanned,
+					Message:            "Every implemented test is planned",
+					LastTransitionTime: now,
+				},
+			},
+		},
+		"below min coverage with invalid tests": {
+			observed: &types.PipelineCoverage{
+				ObjectMeta: metav1.ObjectMeta{Name: "gcp", Generation: 3},
+				Spec:       types.PipelineCoverageSpec{MinCoverage: 80},
+				Result: types.PipelineCoverageResult{
+					Conditions: []types.PipelineCoverageCondition{
+						{
+							Type:               types.PipelineCoverageConfigLoaded,
+							Status:             types.ConditionTrue,
+							LastTransitionTime: earlier,
+							ObservedGeneration: 2,
+						},
+						{
+							Type:               types.PipelineCoverageCoverageMet,
+							Status:             types.ConditionTrue,
+							LastTransitionTime: earlier,
+							ObservedGeneration: 2,
+						},
+					},
+				},
+			},
+			metrics:      plannedTwo,
+			validTests:   []string{"TCID-A"},
+			invalidTests: []string{"TCID-X"},
+			coverage:     .5,
+			expect: []types.PipelineCoverageCondition{
+				{
+					Type:               types.PipelineCoverageConfigLoaded,
+					Status:             types.ConditionTrue,
+					Reason:             ReasonConfigLoaded,
+					Message:            "Loaded 2 planned & 2 implemented tests",
+					LastTransitionTime: earlier,
+					ObservedGeneration: 3,
+				},
+				{
+					Type:               types.PipelineCoveragePlanValid,
+					Status:             types.ConditionTrue,
+					Reason:             ReasonTestsPlanned,
+					Message:            "2 tests are planned",
+					LastTransitionTime: now,
+					ObservedGeneration: 3,
+				},
+				{
+					Type:               types.PipelineCoverageCoverageMet,
+					Status:             types.ConditionFalse,
+					Reason:             ReasonBelowMinCoverage,
+					Message:            "Coverage 50% (1/2) is below 80%",
+					LastTransitionTime: now,
+					ObservedGeneration: 3,
+				},
+				{
+					Type:               types.PipelineCoverageNoInvalidTests,
+					Status:             types.ConditionFalse,
+					Reason:             ReasonInvalidTestsFound,
+					Message:            "1 tests are implemented but not planned [TCID-X]",
+					LastTransitionTime: now,
+					ObservedGeneration: 3,
+				},
+			},
+		},
+		"min coverage met": {
+			observed: &types.PipelineCoverage{
+				Spec: types.PipelineCoverageSpec{MinCoverage: 50},
+			},
+			metrics:    plannedTwo,
+			validTests: []string{"TCID-A"},
+			coverage:   .5,
+			expect: []types.PipelineCoverageCondition{
+				{
+					Type:               types.PipelineCoverageConfigLoaded,
+					Status:             types.ConditionTrue,
+					Reason:             ReasonConfigLoaded,
+					Message:            "Loaded 2 planned & 2 implemented tests",
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoveragePlanValid,
+					Status:             types.ConditionTrue,
+					Reason:             ReasonTestsPlanned,
+					Message:            "2 tests are planned",
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoverageCoverageMet,
+					Status:             types.ConditionTrue,
+					Reason:             ReasonMinCoverageMet,
+					Message:            "Coverage 50% is at least 50%",
+					LastTransitionTime: now,
+				},
+				{
+					Type:               types.PipelineCoverageNoInvalidTests,
+					Status:             types.ConditionTrue,
+					Reason:             ReasonAllTestsPlanned,
+					Message:            "Every implemented test is planned",
+					LastTransitionTime: now,
+				},
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(ReconcilerConfig{
+				Log: logstesting.TestLogger{T: t},
+			})
+			r.now = func() time.Time {
+				return now.Time
+			}
+			r.observed = mock.observed
+			r.metrics = mock.metrics
+			r.validTests = mock.validTests
+			r.invalidTests = mock.invalidTests
+			r.coverage = mock.coverage
+			r.err = mock.err
+			got := r.getConditions()
+			if !reflect.DeepEqual(got, mock.expect) {
+				t.Fatalf("Expected no diff got\n%s", cmp.Diff(mock.expect, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coverage
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"mayadata.io/e2e-metrics/types"
+)
+
+// Reasons of the events recorded against PipelineCoverage
+const (
+	EventReasonCoverageIncreased    string = "CoverageIncreased"
+	EventReasonCoverageDropped      string = "CoverageDropped"
+	EventReasonInvalidTestsDetected string = "InvalidTestsDetected"
+	EventReasonConfigLoadFailed     string = "ConfigLoadFailed"
+	EventReasonUndeclaredValues     string = "UndeclaredValuesFound"
+)
+
+// parsePercentage returns the percent of the given coverage e.g. 50
+// for 50%
+func parsePercentage(coverage string) (int, bool) {
+	percent, err := strconv.Atoi(strings.TrimSuffix(coverage, "%"))
+	if err != nil || !strings.HasSuffix(coverage, "%") {
+		return 0, false
+	}
+	return percent, true
+}
+
+// recordEvents records the changes of the calculated coverage
+// w.r.t the result of the observed PipelineCoverage
+//
+// NOTE:
+//	The same event is recorded as long as the observed result is
+// not updated e.g. during resyncs. Recorder is expected to aggregate
+// such duplicates. The event recorder of client-go does this by
+// incrementing the count of the earlier event.
+func (r *Reconciler) recordEvents() {
+	if r.recorder == nil || r.ObservedPipelineCoverage == nil {
+		return
+	}
+	object := r.ObservedPipelineCoverage
+	if r.err != nil {
+		r.recorder.Event(
+			object,
+			corev1.EventTypeWarning,
+			EventReasonConfigLoadFailed,
+			r.err.Error(),
+		)
+		return
+	}
+
+	observed := r.observed.Result
+	if observed.Phase == types.PipelineCoveragePassed {
+		// coverage is compared only if it was calculated earlier
+		old, isOld := parsePercentage(observed.Coverage)
+		current := int(math.Round(float64(r.coverage) * 100))
+		if isOld && current > old {
+			r.recorder.Eventf(
+				object,
+				corev1.EventTypeNormal,
+				EventReasonCoverageIncreased,
+				"Coverage increased from %d%% to %d%%",
+				old,
+				current,
+			)
+		}
+		if isOld && current < old {
+			r.recorder.Eventf(
+				object,
+				corev1.EventTypeWarning,
+				EventReasonCoverageDropped,
+				"Coverage dropped from %d%% to %d%%",
+				old,
+				current,
+			)
+		}
+	}
+
+	if r.hasNewInvalidTests() {
+		r.recorder.Event(
+			object,
+			corev1.EventTypeWarning,
+			EventReasonInvalidTestsDetected,
+			r.getInvalidTestsMessage(),
+		)
+	}
+
+	// values are declared in the taxonomy of the controller & not
+	// in the PipelineCoverage. Hence, these are flagged as long as
+	// they are found.
+	if len(r.metrics.UndeclaredValues) > 0 {
+		r.recorder.Event(
+			object,
+			corev1.EventTypeWarning,
+			EventReasonUndeclaredValues,
+			r.getUndeclaredValuesMessage(),
+		)
+	}
+}
+
+// hasNewInvalidTests returns true if any of the invalid tests was
+// not invalid as per the observed result
+//
+// NOTE:
+//	Observed invalid tests are capped to MaxResultTestNames sorted
+// names. Tests sorted after the last observed name are unknown.
+// These are considered new only if the count of invalid tests grew.
+func (r *Reconciler) hasNewInvalidTests() bool {
+	observed := r.observed.Result
+	var known = map[string]bool{}
+	for _, tcid := range observed.InvalidTests {
+		known[tcid] = true
+	}
+	var last string
+	var isCapped = int64(len(observed.InvalidTests)) < observed.InvalidTestCount
+	if isCapped && len(observed.InvalidTests) > 0 {
+		last = observed.InvalidTests[len(observed.InvalidTests)-1]
+	}
+	for _, tcid := range r.invalidTests {
+		if known[tcid] {
+			continue
+		}
+		if isCapped && tcid > last {
+			if int64(len(r.invalidTests)) > observed.InvalidTestCount {
+				return true
+			}
+			continue
+		}
+		return true
+	}
+	return false
+}
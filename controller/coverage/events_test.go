@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coverage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+
+	"mayadata.io/e2e-metrics/config"
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+	"mayadata.io/e2e-metrics/types"
+)
+
+func TestReconcilerRecordEvents(t *testing.T) {
+	var tests = map[string]struct {
+		observedResult   types.PipelineCoverageResult
+		coverage         float32
+		invalidTests     []string
+		undeclaredValues []config.UndeclaredValue
+		err              error
+		expect           []string
+	}{
+		"first calculation": {
+			coverage: .5,
+		},
+		"coverage increased": {
+			observedResult: types.PipelineCoverageResult{
+				Phase:    types.PipelineCoveragePassed,
+				Coverage: "40%",
+			},
+			coverage: .5,
+			expect: []string{
+				"Normal CoverageIncreased Coverage increased from 40% to 50%",
+			},
+		},
+		"coverage dropped": {
+			observedResult: types.PipelineCoverageResult{
+				Phase:    types.PipelineCoveragePassed,
+				Coverage: "75%",
+			},
+			coverage: .5,
+			expect: []string{
+				"Warning CoverageDropped Coverage dropped from 75% to 50%",
+			},
+		},
+		"coverage of failed result is not compared": {
+			observedResult: types.PipelineCoverageResult{
+				Phase:    types.PipelineCoverageFailed,
+				Coverage: "0%",
+			},
+			coverage: .5,
+		},
+		"unchanged coverage": {
+			observedResult: types.PipelineCoverageResult{
+				Phase:    types.PipelineCoveragePassed,
+				Coverage: "50%",
+			},
+			coverage: .5049,
+		},
+		"invalid tests detected": {
+			observedResult: types.PipelineCoverageResult{
+				Phase:            types.PipelineCoveragePassed,
+				Coverage:         "50%",
+				InvalidTestCount: 1,
+				InvalidTests:     []string{"TCID-X"},
+			},
+			coverage:     .5,
+			invalidTests: []string{"TCID-Y", "TCID-X"},
+			expect: []string{
+				"Warning InvalidTestsDetected 2 tests are implemented but not planned [TCID-X, TCID-Y]",
+			},
+		},
+		"known invalid tests": {
+			observedResult: types.PipelineCoverageResult{
+				Phase:            types.PipelineCoveragePassed,
+				Coverage:         "50%",
+				InvalidTestCount: 1,
+				InvalidTests:     []string{"TCID-X"},
+			},
+			coverage:     .5,
+			invalidTests: []string{"TCID-X"},
+		},
+		"invalid test replaced by another": {
+			observedResult: types.PipelineCoverageResult{
+				Phase:            types.PipelineCoveragePassed,
+				Coverage:         "50%",
+				InvalidTestCount: 1,
+				InvalidTests:     []string{"TCID-X"},
+			},
+			coverage:     .5,
+			invalidTests: []string{"TCID-Y"},
+			expect: []string{
+				"Warning InvalidTestsDetected 1 tests are implemented but not planned [TCID-Y]",
+			},
+		},
+		"invalid tests beyond the observed names": {
+			observedResult: types.PipelineCoverageResult{
+				Phase:            types.PipelineCoveragePassed,
+				Coverage:         "50%",
+				InvalidTestCount: 2,
+				InvalidTests:     []string{"TCID-X"},
+			},
+			coverage:     .5,
+			invalidTests: []string{"TCID-X", "TCID-Z"},
+		},
+		"undeclared values found": {
+			observedResult: types.PipelineCoverageResult{
+				Phase:    types.PipelineCoveragePassed,
+				Coverage: "50%",
+			},
+			coverage: .5,
+			undeclaredValues: []config.UndeclaredValue{
+				{TCID: "TCID-X", Dimension: "component", Value: "maya"},
+			},
+			expect: []string{
+				`Warning UndeclaredValuesFound 1 undeclared values were found [TCID-X has undeclared component "maya"]`,
+			},
+		},
+		"config load failed": {
+			observedResult: types.PipelineCoverageResult{
+				Phase:    types.PipelineCoveragePassed,
+				Coverage: "50%",
+			},
+			err: errors.New("open .master-plan.yml: no such file or directory"),
+			expect: []string{
+				"Warning ConfigLoadFailed open .master-plan.yml: no such file or directory",
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := NewReconciler(ReconcilerConfig{
+				Log:                      logstesting.TestLogger{T: t},
+				ObservedPipelineCoverage: &unstructured.Unstructured{},
+				Recorder:                 recorder,
+			})
+			r.observed = &types.PipelineCoverage{Result: mock.observedResult}
+			r.metrics = &config.TestCasesMetrics{
+				UndeclaredValues: mock.undeclaredValues,
+			}
+			r.coverage = mock.coverage
+			r.invalidTests = mock.invalidTests
+			r.err = mock.err
+			r.recordEvents()
+			close(recorder.Events)
+			var got []string
+			for event := range recorder.Events {
+				got = append(got, event)
+			}
+			if !reflect.DeepEqual(got, mock.expect) {
+				t.Fatalf("Expected no diff got\n%s", cmp.Diff(mock.expect, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coverage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PolicyRule identifies a rule of a policy
+type PolicyRule string
+
+const (
+	// PolicyRuleMinCoverage is violated if the coverage is below
+	// the minimum
+	PolicyRuleMinCoverage PolicyRule = "MinCoverage"
+
+	// PolicyRuleMaxInvalidTests is violated if there are more
+	// invalid tests than allowed
+	PolicyRuleMaxInvalidTests PolicyRule = "MaxInvalidTests"
+
+	// PolicyRuleNoDeprecatedTests is violated if any test uses the
+	// deprecated tcid- prefix
+	PolicyRuleNoDeprecatedTests PolicyRule = "NoDeprecatedTests"
+
+	// PolicyRuleNoDuplicateTests is violated if any test is planned
+	// or implemented more than once
+	PolicyRuleNoDuplicateTests PolicyRule = "NoDuplicateTests"
+)
+
+// Policy has the rules that a coverage is checked against
+type Policy struct {
+	// MinCoverage is the minimum coverage in percent e.g. 80
+	MinCoverage float64
+
+	// MaxInvalidTests is the maximum number of invalid tests. A
+	// negative value allows any number of invalid tests.
+	MaxInvalidTests int
+
+	NoDeprecatedTests bool
+	NoDuplicateTests  bool
+}
+
+// Violation is a rule of the policy that is not met
+type Violation struct {
+	Rule    PolicyRule
+	Message string
+}
+
+// String implements Stringer interface
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// isBelowPercent returns true if the ratio of the given valid to
+// planned test counts is below the given percent
+//
+// NOTE:
+//	Counts are compared instead of the rounded coverage. Hence, 2/3
+// i.e. 66.67% is below 67%.
+func isBelowPercent(valid, planned int, percent float64) bool {
+	if planned == 0 {
+		return percent > 0
+	}
+	return float64(valid)*100 < percent*float64(planned)
+}
+
+// Check returns the violations of the given summary. Violations
+// are returned in the order the rules are declared in Policy.
+func (p Policy) Check(s *Summary) []Violation {
+	var violations []Violation
+	if isBelowPercent(len(s.ValidTests), s.PlannedTestCount, p.MinCoverage) {
+		violations = append(violations, Violation{
+			Rule: PolicyRuleMinCoverage,
+			Message: fmt.Sprintf(
+				"Coverage %s (%d/%d) is below %g%%",
+				s.Coverage,
+				len(s.ValidTests),
+				s.PlannedTestCount,
+				p.MinCoverage,
+			),
+		})
+	}
+	if p.MaxInvalidTests >= 0 && len(s.InvalidTests) > p.MaxInvalidTests {
+		violations = append(violations, Violation{
+			Rule: PolicyRuleMaxInvalidTests,
+			Message: fmt.Sprintf(
+				"%d invalid tests are more than %d: Register these in the plan [%s]",
+				len(s.InvalidTests),
+				p.MaxInvalidTests,
+				strings.Join(s.InvalidTests, ", "),
+			),
+		})
+	}
+	if p.NoDeprecatedTests && len(s.DeprecatedTests) > 0 {
+		violations = append(violations, Violation{
+			Rule: PolicyRuleNoDeprecatedTests,
+			Message: fmt.Sprintf(
+				"%d tests use the deprecated tcid- prefix: Rename these to TCID- [%s]",
+				len(s.DeprecatedTests),
+				strings.Join(s.DeprecatedTests, ", "),
+			),
+		})
+	}
+	if p.NoDuplicateTests && len(s.DuplicateTests) > 0 {
+		violations = append(violations, Violation{
+			Rule: PolicyRuleNoDuplicateTests,
+			Message: fmt.Sprintf(
+				"%d tests are planned or implemented more than once [%s]",
+				len(s.DuplicateTests),
+				strings.Join(s.DuplicateTests, ", "),
+			),
+		})
+	}
+	return violations
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coverage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	summary := &Summary{
+		ValidTests:       []string{"TCID-A"},
+		InvalidTests:     []string{"TCID-X"},
+		MissingTests:     []string{"TCID-B"},
+		DeprecatedTests:  []string{"tcid-c"},
+		DuplicateTests:   []string{"TCID-A"},
+		PlannedTestCount: 2,
+		Coverage:         .5,
+	}
+	var tests = map[string]struct {
+		policy      Policy
+		expectRules []PolicyRule
+	}{
+		"no rules": {
+			policy: Policy{MaxInvalidTests: -1},
+		},
+		"coverage equals minimum": {
+			policy: Policy{MinCoverage: 50, MaxInvalidTests: -1},
+		},
+		"coverage rounds to but is below minimum": {
+			policy:      Policy{MinCoverage: 50.4, MaxInvalidTests: -1},
+			expectRules: []PolicyRule{PolicyRuleMinCoverage},
+		},
+		"coverage below minimum": {
+			policy:      Policy{MinCoverage: 80, MaxInvalidTests: -1},
+			expectRules: []PolicyRule{PolicyRuleMinCoverage},
+		},
+		"invalid tests within limit": {
+			policy: Policy{MaxInvalidTests: 1},
+		},
+		"all rules": {
+			policy: Policy{
+				MinCoverage:       80,
+				MaxInvalidTests:   0,
+				NoDeprecatedTests: true,
+				NoDuplicateTests:  true,
+			},
+			expectRules: []PolicyRule{
+				PolicyRuleMinCoverage,
+				PolicyRuleMaxInvalidTests,
+				PolicyRuleNoDeprecatedTests,
+				PolicyRuleNoDuplicateTests,
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var got []PolicyRule
+			for _, violation := range mock.policy.Check(summary) {
+				if violation.Message == "" {
+					t.Fatalf("Expected message for %s got none", violation.Rule)
+				}
+				got = append(got, violation.Rule)
+			}
+			if !reflect.DeepEqual(got, mock.expectRules) {
+				t.Fatalf("Expected violations %v got %v", mock.expectRules, got)
+			}
+		})
+	}
+}
+
+func TestIsBelowPercent(t *testing.T) {
+	var tests = map[string]struct {
+		valid   int
+		planned int
+		percent float64
+		expect  bool
+	}{
+		"two thirds is below 67": {
+			valid: 2, planned: 3, percent: 67, expect: true,
+		},
+		"two thirds is not below 66.66": {
+			valid: 2, planned: 3, percent: 66.66,
+		},
+		"79.5 is below 80": {
+			valid: 159, planned: 200, percent: 80, expect: true,
+		},
+		"80 is not below 80": {
+			valid: 4, planned: 5, percent: 80,
+		},
+		"nothing planned is below any minimum": {
+			percent: 1, expect: true,
+		},
+		"nothing planned is not below 0": {},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := isBelowPercent(mock.valid, mock.planned, mock.percent)
+			if got != mock.expect {
+				t.Fatalf("Expected %t got %t", mock.expect, got)
+			}
+		})
+	}
+}
@@ -20,16 +20,23 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 	"openebs.io/metac/controller/generic"
 
 	"mayadata.io/e2e-metrics/config"
 	prom "mayadata.io/e2e-metrics/metrics"
 	"mayadata.io/e2e-metrics/pkg/metac"
+	"mayadata.io/e2e-metrics/report"
 	"mayadata.io/e2e-metrics/types"
 )
 
@@ -56,17 +63,83 @@ func (e *errHandler) handle(err error) {
 	err = nil
 }
 
+// DefaultConfigPath is the directory that has the config files of
+// e2e-metrics
+const DefaultConfigPath string = "/etc/config/e2e-metrics/"
+
+// MaxResultTestNames is the maximum number of test case names that
+// are set in the result of PipelineCoverage. This keeps the size of
+// PipelineCoverage bounded irrespective of the number of tests.
+const MaxResultTestNames int = 50
+
 // Syncable helps in reconciling PipelineCoverage custom resource
 type Syncable struct {
 	log  logr.Logger
 	prom *prom.Metrics
+
+	configPath     string
+	desiredSources []config.SourceConfig
+	actualSources  []config.SourceConfig
+	taxonomy       *config.Taxonomy
+	reportDir      string
+	reportFormats  []string
+	recorder       record.EventRecorder
+
+	// reconcileLock serializes the reconciles of syncs & resyncs.
+	// Hence, metrics are set by one reconcile at a time.
+	reconcileLock sync.Mutex
+
+	// PipelineCoverage(s) & ConfigMaps observed during the last
+	// sync. These are used to resync on config changes.
+	lock              sync.Mutex
+	observedCoverages []*unstructured.Unstructured
+	configMaps        map[string]*unstructured.Unstructured
+
+	// pipeline ids of the PipelineCoverage(s) whose metrics were set
+	// during the last reconcile. These are mapped by their names.
+	metricPipelineIDs map[string]string
+}
+
+// SyncerConfig is used to create a new instance of Syncable
+type SyncerConfig struct {
+	Log  logr.Logger
+	Prom *prom.Metrics
+
+	// ConfigPath is the directory that has the config files. It
+	// defaults to DefaultConfigPath.
+	ConfigPath string
+
+	// Sources to load test cases from if these are not set in
+	// PipelineCoverage. Default sources are used if these are not
+	// set either.
+	DesiredSources []config.SourceConfig
+	ActualSources  []config.SourceConfig
+
+	// Taxonomy has the dimensions of test count metrics & their
+	// allowed values. Default taxonomy is used if this is not set.
+	Taxonomy *config.Taxonomy
+
+	// ReportDir when set has the reports of each PipelineCoverage
+	// in each of the ReportFormats
+	ReportDir     string
+	ReportFormats []string
+
+	// Recorder when set records the events of each PipelineCoverage
+	Recorder record.EventRecorder
 }
 
 // NewSyncer returns a new instance of Syncable
-func NewSyncer(log logr.Logger, prom *prom.Metrics) *Syncable {
+func NewSyncer(conf SyncerConfig) *Syncable {
 	return &Syncable{
-		log:  log,
-		prom: prom,
+		log:            conf.Log,
+		prom:           conf.Prom,
+		configPath:     conf.ConfigPath,
+		desiredSources: conf.DesiredSources,
+		actualSources:  conf.ActualSources,
+		taxonomy:       conf.Taxonomy,
+		reportDir:      conf.ReportDir,
+		reportFormats:  conf.ReportFormats,
+		recorder:       conf.Recorder,
 	}
 }
 
@@ -79,10 +152,14 @@ func NewSyncer(log logr.Logger, prom *prom.Metrics) *Syncable {
 //
 // NOTE:
 //	SyncHookRequest uses Namespace as the watched resource.
-// SyncHookResponse has PipelineCoverage that forms the desired
+// SyncHookResponse has PipelineCoverage(s) that form the desired
 // state w.r.t this watched resource.
 //
 // NOTE:
+//	PipelineCoverage(s) are created by users. Each of these is
+// reconciled separately based on its own spec.
+//
+// NOTE:
 //	Returning error will panic this process. We would rather want
 // this controller to run continuously. Hence, the errors are handled.
 func (s *Syncable) Sync(
@@ -126,25 +203,34 @@ func (s *Syncable) Sync(
 	var err error
 	defer errHandler.handle(err)
 
-	var observedCoverage *unstructured.Unstructured
+	var observedCoverages []*unstructured.Unstructured
+	var configMaps = map[string]*unstructured.Unstructured{}
 	for _, attachment := range request.Attachments.List() {
-		if attachment.GetKind() == "PipelineCoverage" &&
+		if attachment.GetKind() == types.KindPipelineCoverage &&
 			attachment.GetNamespace() == podNS {
-			observedCoverage = attachment
-		} else {
-			// Add un required attachments to response.
-			// Metac in turn ignores them
-			response.Attachments = append(response.Attachments, attachment)
+			observedCoverages = append(observedCoverages, attachment)
+			continue
+		}
+		if attachment.GetKind() == types.KindConfigMap &&
+			attachment.GetNamespace() == podNS {
+			// config maps are only read & are hence added to
+			// the response as is
+			configMaps[attachment.GetName()] = attachment
 		}
+		// Add un required attachments to response.
+		// Metac in turn ignores them
+		response.Attachments = append(response.Attachments, attachment)
+	}
+	if len(observedCoverages) == 0 {
+		log.V(3).Info(
+			"No PipelineCoverage found", "namespace", request.Watch.GetName(),
+		)
 	}
 
-	reconciler := NewReconciler(ReconcilerConfig{
-		Log:                      log,
-		Prom:                     s.prom,
-		ObservedPipelineCoverage: observedCoverage,
-	})
-	desired := reconciler.Reconcile()
-	response.Attachments = append(response.Attachments, desired)
+	response.Attachments = append(
+		response.Attachments,
+		s.syncAll(observedCoverages, configMaps)...,
+	)
 
 	log.V(2).Info(
 		"Sync completed",
@@ -155,6 +241,121 @@ func (s *Syncable) Sync(
 	return nil
 }
 
+// syncAll saves the given observed state for later resyncs &
+// returns the desired states of the given PipelineCoverage(s)
+func (s *Syncable) syncAll(
+	observedCoverages []*unstructured.Unstructured,
+	configMaps map[string]*unstructured.Unstructured,
+) []*unstructured.Unstructured {
+	s.reconcileLock.Lock()
+	defer s.reconcileLock.Unlock()
+
+	s.lock.Lock()
+	s.observedCoverages = observedCoverages
+	s.configMaps = configMaps
+	s.lock.Unlock()
+
+	return s.reconcileAll(observedCoverages, configMaps, s.recorder)
+}
+
+// reconcileAll reconciles each of the given PipelineCoverage(s) &
+// returns their desired states. Events are recorded with the given
+// recorder if it is set.
+//
+// NOTE:
+//	Caller is expected to hold reconcileLock
+func (s *Syncable) reconcileAll(
+	observedCoverages []*unstructured.Unstructured,
+	configMaps map[string]*unstructured.Unstructured,
+	recorder record.EventRecorder,
+) []*unstructured.Unstructured {
+	var desiredCoverages []*unstructured.Unstructured
+	var pipelineIDs = map[string]string{}
+	for _, observed := range observedCoverages {
+		reconciler := NewReconciler(ReconcilerConfig{
+			Log:                      s.log,
+			Prom:                     s.prom,
+			ObservedPipelineCoverage: observed,
+			ConfigMaps:               configMaps,
+			ConfigPath:               s.configPath,
+			DesiredSources:           s.desiredSources,
+			ActualSources:            s.actualSources,
+			Taxonomy:                 s.taxonomy,
+			ReportDir:                s.reportDir,
+			ReportFormats:            s.reportFormats,
+			Recorder:                 recorder,
+		})
+		desired, err := reconciler.Reconcile()
+		if reconciler.observed != nil {
+			pipelineIDs[observed.GetName()] = reconciler.observed.Spec.Pipeline.ID
+		}
+		if err != nil {
+			s.log.Error(
+				err,
+				"Failed to reconcile PipelineCoverage",
+				"name", observed.GetName(),
+			)
+			// observed state is retained to avoid its deletion
+			desiredCoverages = append(desiredCoverages, observed)
+			continue
+		}
+		desiredCoverages = append(desiredCoverages, desired)
+	}
+	s.deleteStaleMetrics(pipelineIDs)
+	return desiredCoverages
+}
+
+// deleteStaleMetrics removes the metrics of the PipelineCoverage(s)
+// that are no longer observed or whose pipeline id has changed
+func (s *Syncable) deleteStaleMetrics(pipelineIDs map[string]string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for name, pipelineID := range s.metricPipelineIDs {
+		current, found := pipelineIDs[name]
+		if found && current == pipelineID {
+			continue
+		}
+		s.log.V(3).Info(
+			"Will delete stale metrics",
+			"name", name,
+			"pipeline-id", pipelineID,
+		)
+		s.prom.DeletePipelineCoverage(pipelineID, name)
+		if !found {
+			s.prom.DeleteTestCaseInfos(name)
+			s.prom.DeleteTestCounts(name)
+		}
+	}
+	s.metricPipelineIDs = pipelineIDs
+}
+
+// Resync reloads the config & reconciles the PipelineCoverage(s)
+// observed during the last sync. It is meant to be invoked when the
+// config files change.
+//
+// NOTE:
+//	This refreshes the metrics & reports right away. The result of
+// PipelineCoverage is set by metac during its next sync since metac
+// is the only one that updates the attachments.
+//
+// NOTE:
+//	Events are not recorded since the observed PipelineCoverage(s)
+// can be stale by now. These are recorded by the next sync.
+func (s *Syncable) Resync() {
+	s.reconcileLock.Lock()
+	defer s.reconcileLock.Unlock()
+
+	// observed state is read after the lock to avoid reconciling
+	// a state older than the one of a sync that just completed
+	s.lock.Lock()
+	observedCoverages := s.observedCoverages
+	configMaps := s.configMaps
+	s.lock.Unlock()
+
+	s.log.V(3).Info("Will resync", "coverages", len(observedCoverages))
+	s.reconcileAll(observedCoverages, configMaps, nil)
+}
+
 // Percentage helps in formating a float value into
 // percent notation
 type Percentage float32
@@ -172,6 +373,19 @@ type Reconciler struct {
 	prom                     *prom.Metrics
 	ObservedPipelineCoverage *unstructured.Unstructured
 
+	configMaps     map[string]*unstructured.Unstructured
+	configPath     string
+	gitRef         string
+	desiredSources []config.SourceConfig
+	actualSources  []config.SourceConfig
+	taxonomy       *config.Taxonomy
+	reportDir      string
+	reportFormats  []string
+	recorder       record.EventRecorder
+
+	// typed form of the observed PipelineCoverage
+	observed *types.PipelineCoverage
+
 	metrics *config.TestCasesMetrics
 
 	// actual & valid test case names
@@ -180,23 +394,76 @@ type Reconciler struct {
 	// actual test case names that are not registered as desired
 	invalidTests []string
 
+	// desired test case names that are not implemented
+	missingTests []string
+
 	coverage float32
 	warnings []string
 	err      error
+
+	// now returns the current time & is set in conditions
+	now func() time.Time
 }
 
+// ReconcilerConfig is used to create a new instance of Reconciler
 type ReconcilerConfig struct {
 	Log                      logr.Logger
 	Prom                     *prom.Metrics
 	ObservedPipelineCoverage *unstructured.Unstructured
+
+	// ConfigMaps that can be referred to by the sources of the
+	// observed PipelineCoverage. These are mapped by their names.
+	ConfigMaps map[string]*unstructured.Unstructured
+
+	// ConfigPath defaults to DefaultConfigPath
+	ConfigPath string
+
+	// GitRef when set reads the files from this ref of the git
+	// repository found at ConfigPath. Git set in the observed
+	// PipelineCoverage takes precedence.
+	GitRef string
+
+	// Sources used if these are not set in the observed
+	// PipelineCoverage
+	DesiredSources []config.SourceConfig
+	ActualSources  []config.SourceConfig
+
+	// Taxonomy has the dimensions of test count metrics & their
+	// allowed values. Test cases with values that are not allowed
+	// are set as warnings.
+	Taxonomy *config.Taxonomy
+
+	// ReportDir when set has the reports of the observed
+	// PipelineCoverage in each of the ReportFormats. Reports are
+	// named after the PipelineCoverage.
+	ReportDir     string
+	ReportFormats []string
+
+	// Recorder when set records the changes of the coverage as
+	// events of the observed PipelineCoverage
+	Recorder record.EventRecorder
 }
 
 // NewReconciler returns a new instance of reconciler
 func NewReconciler(conf ReconcilerConfig) *Reconciler {
+	configPath := conf.ConfigPath
+	if configPath == "" {
+		configPath = DefaultConfigPath
+	}
 	return &Reconciler{
 		log:                      conf.Log,
 		prom:                     conf.Prom,
 		ObservedPipelineCoverage: conf.ObservedPipelineCoverage,
+		configMaps:               conf.ConfigMaps,
+		configPath:               configPath,
+		gitRef:                   conf.GitRef,
+		desiredSources:           conf.DesiredSources,
+		actualSources:            conf.ActualSources,
+		taxonomy:                 conf.Taxonomy,
+		reportDir:                conf.ReportDir,
+		reportFormats:            conf.ReportFormats,
+		recorder:                 conf.Recorder,
+		now:                      time.Now,
 	}
 }
 
@@ -233,7 +500,7 @@ func (r *Reconciler) getDeprecatedOrEmpty() string {
 	return fmt.Sprintf(
 		"%s: %s",
 		dcount,
-		strings.Join(r.metrics.DeprecatedTestCases, ": "),
+		strings.Join(r.metrics.DeprecatedTCIDs(), ": "),
 	)
 }
 
@@ -241,7 +508,7 @@ func (r *Reconciler) getDeprecatedOrEmpty() string {
 // test coverage percentage including setting warnings if any
 func (r *Reconciler) calculateCoverage() {
 	for tcid := range r.metrics.ActualTestCases {
-		if r.metrics.DesiredTestCases[tcid] {
+		if _, found := r.metrics.DesiredTestCases[tcid]; found {
 			// the gitlab-ci.yml test case(s) that are registered
 			// in .master-plan.yml are valid
 			r.validTests = append(r.validTests, tcid)
@@ -249,6 +516,14 @@ func (r *Reconciler) calculateCoverage() {
 			r.invalidTests = append(r.invalidTests, tcid)
 		}
 	}
+	for tcid := range r.metrics.DesiredTestCases {
+		if _, found := r.metrics.ActualTestCases[tcid]; !found {
+			// the .master-plan.yml test case(s) that are not
+			// found in gitlab-ci.yml are missing
+			r.missingTests = append(r.missingTests, tcid)
+		}
+	}
+	sort.Strings(r.missingTests)
 	if len(r.invalidTests) > 0 {
 		r.warnings = append(
 			r.warnings,
@@ -260,6 +535,10 @@ func (r *Reconciler) calculateCoverage() {
 		)
 	}
 
+	if len(r.metrics.UndeclaredValues) > 0 {
+		r.warnings = append(r.warnings, r.getUndeclaredValuesMessage())
+	}
+
 	validTestCount := len(r.validTests)
 	desiredTestCount := len(r.metrics.DesiredTestCases)
 	if desiredTestCount == 0 {
@@ -275,20 +554,205 @@ func (r *Reconciler) calculateCoverage() {
 	r.coverage = actual / desired
 }
 
+// getUndeclaredValuesMessage returns the message that lists the
+// values of test cases that are not declared in the taxonomy
+func (r *Reconciler) getUndeclaredValuesMessage() string {
+	var undeclared []string
+	for _, value := range r.metrics.UndeclaredValues {
+		undeclared = append(undeclared, value.String())
+	}
+	return fmt.Sprintf(
+		"%d undeclared values were found [%s]",
+		len(undeclared),
+		strings.Join(undeclared, ", "),
+	)
+}
+
+// SpecError is returned if the spec of a PipelineCoverage is invalid
+type SpecError struct {
+	Field string
+	Msg   string
+}
+
+// Error implements error interface
+func (e *SpecError) Error() string {
+	return fmt.Sprintf("Invalid %s: %s", e.Field, e.Msg)
+}
+
+// validatePath returns an error if the given path of the given field
+// is not within the config directory
+func validatePath(field, name string) error {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+		return &SpecError{
+			Field: field,
+			Msg:   fmt.Sprintf("Path %q must be relative", name),
+		}
+	}
+	clean := filepath.ToSlash(filepath.Clean(filepath.FromSlash(name)))
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return &SpecError{
+			Field: field,
+			Msg:   fmt.Sprintf("Path %q is outside the config directory", name),
+		}
+	}
+	return nil
+}
+
+// validatePaths returns an error if any path of the given spec that
+// is read from the config directory is not within this directory
+func validatePaths(spec types.PipelineCoverageSpec) error {
+	if spec.Git != nil {
+		if err := validatePath("spec.git.path", spec.Git.Path); err != nil {
+			return err
+		}
+	}
+	for _, sources := range []struct {
+		field string
+		specs []types.SourceSpec
+	}{
+		{"spec.plan", spec.Plan},
+		{"spec.ci", spec.CI},
+	} {
+		for i, source := range sources.specs {
+			if source.ConfigMap != nil {
+				// paths of a ConfigMap are its keys
+				continue
+			}
+			err := validatePath(fmt.Sprintf("%s[%d].path", sources.field, i), source.Path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// toSourceConfigs returns the source configs corresponding to the
+// given specs or the given defaults if there are no specs
+func (r *Reconciler) toSourceConfigs(
+	specs []types.SourceSpec,
+	defaults []config.SourceConfig,
+) ([]config.SourceConfig, error) {
+	if len(specs) == 0 {
+		return defaults, nil
+	}
+	var confs []config.SourceConfig
+	for _, spec := range specs {
+		conf := config.SourceConfig{
+			Format:   spec.Format,
+			Path:     spec.Path,
+			Optional: spec.Optional,
+		}
+		if spec.ConfigMap != nil {
+			files, err := r.getConfigMapFiles(spec.ConfigMap.Name)
+			if err != nil {
+				return nil, err
+			}
+			// local includes are resolved from the same ConfigMap
+			conf.Files = files
+			conf.IncludeFiles = files
+			if conf.Path == "" {
+				conf.Path = spec.ConfigMap.Key
+			}
+			if conf.Path == "" {
+				conf.Path = "*"
+			}
+		}
+		confs = append(confs, conf)
+	}
+	return confs, nil
+}
+
+// getConfigMapFiles returns a reader of the files i.e. the data of
+// the given ConfigMap
+func (r *Reconciler) getConfigMapFiles(name string) (config.FileReader, error) {
+	configMap, found := r.configMaps[name]
+	if !found {
+		return nil, errors.Errorf("ConfigMap %q not found", name)
+	}
+	data, _, err := unstructured.NestedStringMap(configMap.Object, "data")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid ConfigMap %q", name)
+	}
+	return config.NewMapReader(data), nil
+}
+
+// usesConfigMapsOnly returns true if all the sources of the given
+// spec refer to ConfigMaps
+func usesConfigMapsOnly(spec types.PipelineCoverageSpec) bool {
+	if len(spec.Plan) == 0 || len(spec.CI) == 0 {
+		return false
+	}
+	for _, sources := range [][]types.SourceSpec{spec.Plan, spec.CI} {
+		for _, source := range sources {
+			if source.ConfigMap == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // loadConfigOrEmpty loads the config or empty if config
 // is not found
+//
+// NOTE:
+//	Config directory is not required if all the sources refer to
+// ConfigMaps. Files are read from a commit of a git repository if
+// git is set in the spec.
 func (r *Reconciler) loadConfigOrEmpty() {
+	var spec types.PipelineCoverageSpec
+	var name, namespace string
+	if r.observed != nil {
+		spec = r.observed.Spec
+		name = r.observed.GetName()
+		namespace = r.observed.GetNamespace()
+	}
+	// set an empty metrics if error
+	r.metrics = &config.TestCasesMetrics{}
+	r.err = validatePaths(spec)
+	if r.err != nil {
+		return
+	}
+	desiredSources, err := r.toSourceConfigs(spec.Plan, r.desiredSources)
+	if err != nil {
+		r.err = err
+		return
+	}
+	actualSources, err := r.toSourceConfigs(spec.CI, r.actualSources)
+	if err != nil {
+		r.err = err
+		return
+	}
+	path := r.configPath
+	gitRef := r.gitRef
+	if spec.Git != nil {
+		path = filepath.Join(r.configPath, spec.Git.Path)
+		gitRef = spec.Git.Ref
+		if gitRef == "" {
+			gitRef = config.DefaultGitRef
+		}
+	} else if usesConfigMapsOnly(spec) {
+		path = ""
+	}
 	c := config.New(config.LoadableConfig{
-		Path: "/etc/config/e2e-metrics/",
-		Log:  r.log,
-		Prom: r.prom,
+		Path:           path,
+		GitRef:         gitRef,
+		Log:            r.log,
+		Prom:           r.prom,
+		DesiredSources: desiredSources,
+		ActualSources:  actualSources,
+		Taxonomy:       r.taxonomy,
+
+		PipelineCoverage: name,
+		Namespace:        namespace,
 	})
 	r.metrics, r.err = c.LoadOrEmpty()
 }
 
-// Reconcile observed state of CStorClusterPlan to its desired
+// Reconcile observed state of PipelineCoverage to its desired
 // state
-func (r *Reconciler) Reconcile() *unstructured.Unstructured {
+func (r *Reconciler) Reconcile() (*unstructured.Unstructured, error) {
 	defer func() {
 		r.prom.IncrementControllerSyncCount(&prom.Controller{
 			Name:  "pipeline-coverage-controller",
@@ -297,6 +761,116 @@ func (r *Reconciler) Reconcile() *unstructured.Unstructured {
 		})
 	}()
 
+	r.observed, r.err = types.PipelineCoverageFromUnstructured(
+		r.ObservedPipelineCoverage,
+	)
+	if r.err != nil {
+		r.recordEvents()
+		return nil, r.err
+	}
+
+	r.calculate()
+	r.writeReports()
+	r.setMetrics()
+	r.recordEvents()
+	return r.getDesiredPipelineCoverage().ToUnstructured()
+}
+
+// setMetrics sets the metrics of the observed PipelineCoverage if
+// the coverage was calculated
+//
+// NOTE:
+//	All the series of the observed PipelineCoverage are removed if
+// the coverage was not calculated. Hence, the coverage & test cases
+// of a config that no longer loads do not linger.
+func (r *Reconciler) setMetrics() {
+	if r.err != nil {
+		r.prom.DeletePipelineCoverage(r.observed.Spec.Pipeline.ID, r.observed.GetName())
+		r.prom.DeleteTestCaseInfos(r.observed.GetName())
+		r.prom.DeleteTestCounts(r.observed.GetName())
+		return
+	}
+	r.prom.SetPipelineCoverage(&prom.PipelineCoverage{
+		PipelineID: r.observed.Spec.Pipeline.ID,
+		Name:       r.observed.GetName(),
+		Coverage: coverageRatio(
+			len(r.validTests),
+			len(r.metrics.DesiredTestCases),
+		),
+		ValidTestCount:      float64(len(r.validTests)),
+		InvalidTestCount:    float64(len(r.invalidTests)),
+		MissingTestCount:    float64(len(r.missingTests)),
+		DeprecatedTestCount: float64(len(r.metrics.DeprecatedTestCases)),
+	})
+	r.prom.SetTestCaseInfos(r.observed.GetName(), r.getTestCaseInfos())
+}
+
+// getTestCaseInfos returns the state of every test case of the
+// calculated coverage
+func (r *Reconciler) getTestCaseInfos() []prom.TestCaseInfo {
+	var infos []prom.TestCaseInfo
+	for tcid, test := range r.metrics.ActualTestCases {
+		state := prom.TestCaseStateValid
+		if _, found := r.metrics.DesiredTestCases[tcid]; !found {
+			state = prom.TestCaseStateInvalid
+		}
+		infos = append(infos, r.newTestCaseInfo(tcid, state, test.ImplementationType))
+	}
+	for _, tcid := range r.missingTests {
+		infos = append(infos, r.newTestCaseInfo(tcid, prom.TestCaseStateMissing, ""))
+	}
+	for tcid, test := range r.metrics.DeprecatedTestCases {
+		infos = append(infos, r.newTestCaseInfo(
+			tcid, prom.TestCaseStateDeprecated, test.ImplementationType,
+		))
+	}
+	return infos
+}
+
+// newTestCaseInfo returns the info of the given test case with the
+// group & type labels of its planned test
+func (r *Reconciler) newTestCaseInfo(
+	tcid string,
+	state prom.TestCaseState,
+	implType prom.TestImplementationType,
+) prom.TestCaseInfo {
+	planned := r.metrics.DesiredTestCases[tcid]
+	return prom.TestCaseInfo{
+		TCID:                   tcid,
+		State:                  state,
+		Group:                  planned.Labels[config.PlannedTestGroupLabelKey],
+		Type:                   planned.Labels[config.PlannedTestTypeLabelKey],
+		TestImplementationType: implType,
+	}
+}
+
+// writeReports writes the reports of the calculated coverage if
+// a report directory is set
+//
+// NOTE:
+//	Failure to write the reports is logged & does not fail the
+// reconciliation since the coverage is still set in the result
+func (r *Reconciler) writeReports() {
+	if r.reportDir == "" || r.err != nil {
+		return
+	}
+	out := r.getSummary().Report()
+	out.Name = r.observed.GetName()
+	out.PipelineID = r.observed.Spec.Pipeline.ID
+	out.RunID = r.observed.Spec.Pipeline.RunID
+	err := report.WriteFiles(r.reportDir, out.Name, r.reportFormats, out)
+	if err != nil {
+		r.log.Error(
+			err,
+			"Failed to write reports",
+			"name", out.Name,
+			"dir", r.reportDir,
+		)
+	}
+}
+
+// calculate loads the config & calculates the coverage
+func (r *Reconciler) calculate() {
 	var fns = []func(){
 		r.loadConfigOrEmpty,
 		r.calculateCoverage,
@@ -309,7 +883,53 @@ func (r *Reconciler) Reconcile() *unstructured.Unstructured {
 			break
 		}
 	}
-	return r.getDesiredPipelineCoverage()
+}
+
+// Calculate loads the config & returns the calculated coverage
+// without any PipelineCoverage. This is meant to be used outside
+// of Kubernetes e.g. by command line tools.
+func (r *Reconciler) Calculate() (*Summary, error) {
+	r.calculate()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.getSummary(), nil
+}
+
+// getSummary returns the sorted test cases of the calculated
+// coverage
+func (r *Reconciler) getSummary() *Summary {
+	summary := &Summary{
+		ValidTests:       sortedCopy(r.validTests),
+		InvalidTests:     sortedCopy(r.invalidTests),
+		MissingTests:     sortedCopy(r.missingTests),
+		DeprecatedTests:  r.metrics.DeprecatedTCIDs(),
+		DuplicateTests:   sortedCopy(r.metrics.DuplicateTestCases),
+		PlannedTestCount: len(r.metrics.DesiredTestCases),
+		Coverage:         Percentage(r.coverage),
+		Commit:           r.metrics.Commit,
+		Warnings:         r.warnings,
+	}
+	return summary
+}
+
+// getCappedMissingTests returns at most MaxResultTestNames missing
+// test cases
+func (r *Reconciler) getCappedMissingTests() []string {
+	if len(r.missingTests) <= MaxResultTestNames {
+		return r.missingTests
+	}
+	return r.missingTests[:MaxResultTestNames]
+}
+
+// getCappedInvalidTests returns at most MaxResultTestNames sorted
+// invalid test cases
+func (r *Reconciler) getCappedInvalidTests() []string {
+	invalidTests := sortedCopy(r.invalidTests)
+	if len(invalidTests) <= MaxResultTestNames {
+		return invalidTests
+	}
+	return invalidTests[:MaxResultTestNames]
 }
 
 // getDesiredPipelineCoverage returns the desired PipelineCoverage
@@ -318,44 +938,37 @@ func (r *Reconciler) Reconcile() *unstructured.Unstructured {
 // NOTE:
 //	The returned instance is idempotent and hence can be used during
 // create & update operations
-func (r *Reconciler) getDesiredPipelineCoverage() *unstructured.Unstructured {
-	coverage := &unstructured.Unstructured{}
-	coverage.SetUnstructuredContent(map[string]interface{}{
-		"metadata": map[string]interface{}{
-			"name":      os.Getenv("E2E_METRICS_COVERAGE_NAME"),
-			"namespace": os.Getenv("MY_POD_NAMESPACE"),
-		},
-		"spec": map[string]interface{}{
-			"pipeline": map[string]interface{}{
-				"id": os.Getenv("E2E_METRICS_PIPELINE_ID"),
-			},
-			"test": map[string]interface{}{
-				"count": int64(len(r.metrics.DesiredTestCases)),
-			},
-		},
-		// since metac does not sync the attachment's status
-		// we are renaming status -> result
-		//
-		// NOTE:
-		//	metac does not reconcile status since it can lead
-		// to hot loop reconciliations. Once metac exposes a
-		// new tunable or starts supporting reconcilining
-		// attachment's status, we may rename result -> status.
-		//
-		// ref - https://github.com/AmitKumarDas/metac/issues/100
-		"result": map[string]interface{}{
-			"phase":            r.getPhase(),
-			"reason":           r.getErrOrEmpty(),
-			"warning":          r.getWarnOrEmpty(),
-			"deprecated":       r.getDeprecatedOrEmpty(),
-			"runid":            os.Getenv("E2E_METRICS_RUN_ID"),
-			"validTestCount":   int64(len(r.validTests)),
-			"invalidTestCount": int64(len(r.invalidTests)),
-			"coverage":         Percentage(r.coverage).String(),
-		},
-	})
+//
+// NOTE:
+//	Spec is retained from the observed instance except for the
+// count of planned tests
+func (r *Reconciler) getDesiredPipelineCoverage() *types.PipelineCoverage {
+	coverage := &types.PipelineCoverage{}
+	if r.observed != nil {
+		coverage.ObjectMeta = metav1.ObjectMeta{
+			Name:      r.observed.GetName(),
+			Namespace: r.observed.GetNamespace(),
+		}
+		coverage.Spec = *r.observed.Spec.DeepCopy()
+	}
+	coverage.Spec.Test.Count = int64(len(r.metrics.DesiredTestCases))
+	coverage.Result = types.PipelineCoverageResult{
+		Phase:            r.getPhase(),
+		Reason:           r.getErrOrEmpty(),
+		Warning:          r.getWarnOrEmpty(),
+		Deprecated:       r.getDeprecatedOrEmpty(),
+		RunID:            coverage.Spec.Pipeline.RunID,
+		Commit:           r.metrics.Commit,
+		ValidTestCount:   int64(len(r.validTests)),
+		InvalidTestCount: int64(len(r.invalidTests)),
+		InvalidTests:     r.getCappedInvalidTests(),
+		MissingTestCount: int64(len(r.missingTests)),
+		MissingTests:     r.getCappedMissingTests(),
+		Coverage:         Percentage(r.coverage).String(),
+		Conditions:       r.getConditions(),
+	}
 	// below is the right way to set APIVersion & Kind
-	coverage.SetAPIVersion(string(types.E2EMetricsMayadataV1Alpha1))
-	coverage.SetKind(string(types.KindPipelineCoverage))
+	coverage.APIVersion = types.E2EMetricsMayadataV1Alpha1
+	coverage.Kind = types.KindPipelineCoverage
 	return coverage
 }
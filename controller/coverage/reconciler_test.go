@@ -17,21 +17,77 @@ limitations under the License.
 package coverage
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 
 	"mayadata.io/e2e-metrics/config"
 	"mayadata.io/e2e-metrics/metrics"
 	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+	"mayadata.io/e2e-metrics/report"
 	"mayadata.io/e2e-metrics/types"
 
+	"openebs.io/metac/controller/common"
 	"openebs.io/metac/controller/generic"
 )
 
+// newCoverage returns an unstructured PipelineCoverage that loads
+// test cases from the given testdata directory
+func newCoverage(name, dir string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": types.E2EMetricsMayadataV1Alpha1,
+			"kind":       types.KindPipelineCoverage,
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"spec": map[string]interface{}{
+				"pipeline": map[string]interface{}{
+					"id":    name,
+					"runid": "run-" + name,
+				},
+				"plan": []interface{}{
+					map[string]interface{}{
+						"format": config.FormatMasterPlan,
+						"path":   dir + "/plan.yml",
+					},
+				},
+				"ci": []interface{}{
+					map[string]interface{}{
+						"format": config.FormatGitlabCI,
+						"path":   dir + "/ci.yml",
+					},
+				},
+			},
+		},
+	}
+}
+
+// newAttachments returns the given objects as attachments of a
+// sync request
+func newAttachments(objs ...*unstructured.Unstructured) common.AnyUnstructRegistry {
+	attachments := common.AnyUnstructRegistry{}
+	for _, obj := range objs {
+		attachments.Insert(obj)
+	}
+	return attachments
+}
+
 func TestSync(t *testing.T) {
 	var tests = map[string]struct {
 		request               *generic.SyncHookRequest
@@ -79,11 +135,103 @@ func TestSync(t *testing.T) {
 			isSkipReconcile:       true,
 			isErr:                 false,
 		},
-		"namespace == pod namespace": {
+		"namespace == pod namespace without coverages": {
+			request: &generic.SyncHookRequest{
+				Watch: &unstructured.Unstructured{
+					Object: map[string]interface{}{},
+				},
+			},
+			response:              &generic.SyncHookResponse{},
+			expectAttachmentCount: 0,
+			isSkipReconcile:       false,
+			isErr:                 false,
+		},
+		"namespace == pod namespace with coverages": {
 			request: &generic.SyncHookRequest{
 				Watch: &unstructured.Unstructured{
 					Object: map[string]interface{}{},
 				},
+				Attachments: newAttachments(
+					newCoverage("gcp", "gcp"),
+					newCoverage("aws", "aws"),
+					&unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"apiVersion": "v1",
+							"kind":       "ConfigMap",
+							"metadata": map[string]interface{}{
+								"name": "e2e",
+							},
+						},
+					},
+				),
+			},
+			response:              &generic.SyncHookResponse{},
+			expectAttachmentCount: 3,
+			isSkipReconcile:       false,
+			isErr:                 false,
+		},
+		"coverage with config map": {
+			request: &generic.SyncHookRequest{
+				Watch: &unstructured.Unstructured{
+					Object: map[string]interface{}{},
+				},
+				Attachments: newAttachments(
+					&unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"apiVersion": types.E2EMetricsMayadataV1Alpha1,
+							"kind":       types.KindPipelineCoverage,
+							"metadata": map[string]interface{}{
+								"name": "e2e",
+							},
+							"spec": map[string]interface{}{
+								"plan": []interface{}{
+									map[string]interface{}{
+										"format": config.FormatMasterPlan,
+										"configMap": map[string]interface{}{
+											"name": "e2e",
+											"key":  "plan.yml",
+										},
+									},
+								},
+							},
+						},
+					},
+					&unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"apiVersion": "v1",
+							"kind":       types.KindConfigMap,
+							"metadata": map[string]interface{}{
+								"name": "e2e",
+							},
+							"data": map[string]interface{}{
+								"plan.yml": "kind: MasterPlan",
+							},
+						},
+					},
+				),
+			},
+			response:              &generic.SyncHookResponse{},
+			expectAttachmentCount: 2,
+			isSkipReconcile:       false,
+			isErr:                 false,
+		},
+		"invalid coverage is retained": {
+			request: &generic.SyncHookRequest{
+				Watch: &unstructured.Unstructured{
+					Object: map[string]interface{}{},
+				},
+				Attachments: newAttachments(
+					&unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"apiVersion": types.E2EMetricsMayadataV1Alpha1,
+							"kind":       types.KindPipelineCoverage,
+							"metadata": map[string]interface{}{
+								"name": "invalid",
+							},
+							"spec": "invalid",
+						},
+					},
+				),
 			},
 			response:              &generic.SyncHookResponse{},
 			expectAttachmentCount: 1,
@@ -99,10 +247,11 @@ func TestSync(t *testing.T) {
 				T: t,
 			}
 			prom := metrics.New(log)
-			s := NewSyncer(
-				log,
-				prom,
-			)
+			s := NewSyncer(SyncerConfig{
+				Log:        log,
+				Prom:       prom,
+				ConfigPath: "testdata",
+			})
 			err := s.Sync(mock.request, mock.response)
 			if mock.isErr && err == nil {
 				t.Fatalf("Expected error got none")
@@ -200,6 +349,60 @@ func TestPercentageString(t *testing.T) {
 	}
 }
 
+func TestValidatePaths(t *testing.T) {
+	var tests = map[string]struct {
+		spec      types.PipelineCoverageSpec
+		expectErr string
+	}{
+		"relative paths": {
+			spec: types.PipelineCoverageSpec{
+				Git:  &types.GitSpec{Path: "repos/e2e"},
+				Plan: []types.SourceSpec{{Path: "plans/../.master-plan.yml"}},
+				CI:   []types.SourceSpec{{Path: ".github/workflows/*.yml"}},
+			},
+		},
+		"absolute git path": {
+			spec: types.PipelineCoverageSpec{
+				Git: &types.GitSpec{Path: "/etc"},
+			},
+			expectErr: `Invalid spec.git.path: Path "/etc" must be relative`,
+		},
+		"plan path outside config directory": {
+			spec: types.PipelineCoverageSpec{
+				Plan: []types.SourceSpec{{Path: "plans/../../.master-plan.yml"}},
+			},
+			expectErr: `Invalid spec.plan[0].path: Path "plans/../../.master-plan.yml" is outside the config directory`,
+		},
+		"ci path outside config directory": {
+			spec: types.PipelineCoverageSpec{
+				CI: []types.SourceSpec{{Path: "ci.yml"}, {Path: ".."}},
+			},
+			expectErr: `Invalid spec.ci[1].path: Path ".." is outside the config directory`,
+		},
+		"config map path is a key": {
+			spec: types.PipelineCoverageSpec{
+				CI: []types.SourceSpec{{
+					Path:      "../ci.yml",
+					ConfigMap: &types.ConfigMapSourceSpec{Name: "ci"},
+				}},
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			err := validatePaths(mock.spec)
+			if mock.expectErr == "" && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if mock.expectErr != "" && (err == nil || err.Error() != mock.expectErr) {
+				t.Fatalf("Expected error %q got %v", mock.expectErr, err)
+			}
+		})
+	}
+}
+
 func TestReconcilerCalculateCoverage(t *testing.T) {
 	var tests = map[string]struct {
 		//reconciler  *Reconciler
@@ -218,12 +421,12 @@ func TestReconcilerCalculateCoverage(t *testing.T) {
 		"1/2 coverage": {
 			//reconciler: &Reconciler{
 			metrics: &config.TestCasesMetrics{
-				ActualTestCases: map[string]bool{
-					"101": true,
+				ActualTestCases: map[string]config.ActualTestCase{
+					"101": {TCID: "101"},
 				},
-				DesiredTestCases: map[string]bool{
-					"101": true,
-					"201": true,
+				DesiredTestCases: map[string]config.PlannedTest{
+					"101": {TCID: "101"},
+					"201": {TCID: "201"},
 				},
 			},
 			//},
@@ -232,13 +435,13 @@ func TestReconcilerCalculateCoverage(t *testing.T) {
 		"1/3 coverage": {
 			//reconciler: &Reconciler{
 			metrics: &config.TestCasesMetrics{
-				ActualTestCases: map[string]bool{
-					"101": true,
+				ActualTestCases: map[string]config.ActualTestCase{
+					"101": {TCID: "101"},
 				},
-				DesiredTestCases: map[string]bool{
-					"101": true,
-					"201": true,
-					"301": true,
+				DesiredTestCases: map[string]config.PlannedTest{
+					"101": {TCID: "101"},
+					"201": {TCID: "201"},
+					"301": {TCID: "301"},
 				},
 			},
 			//},
@@ -247,13 +450,13 @@ func TestReconcilerCalculateCoverage(t *testing.T) {
 		"2/2 coverage": {
 			//reconciler: &Reconciler{
 			metrics: &config.TestCasesMetrics{
-				ActualTestCases: map[string]bool{
-					"101": true,
-					"201": true,
+				ActualTestCases: map[string]config.ActualTestCase{
+					"101": {TCID: "101"},
+					"201": {TCID: "201"},
 				},
-				DesiredTestCases: map[string]bool{
-					"101": true,
-					"201": true,
+				DesiredTestCases: map[string]config.PlannedTest{
+					"101": {TCID: "101"},
+					"201": {TCID: "201"},
 				},
 			},
 			//},
@@ -262,13 +465,13 @@ func TestReconcilerCalculateCoverage(t *testing.T) {
 		"1/2 coverage - actuals != desired": {
 			//reconciler: &Reconciler{
 			metrics: &config.TestCasesMetrics{
-				ActualTestCases: map[string]bool{
-					"101": true,
-					"301": true, // not registered in desired
+				ActualTestCases: map[string]config.ActualTestCase{
+					"101": {TCID: "101"},
+					"301": {TCID: "301"}, // not registered in desired
 				},
-				DesiredTestCases: map[string]bool{
-					"101": true,
-					"201": true,
+				DesiredTestCases: map[string]config.PlannedTest{
+					"101": {TCID: "101"},
+					"201": {TCID: "201"},
 				},
 			},
 			//},
@@ -278,15 +481,15 @@ func TestReconcilerCalculateCoverage(t *testing.T) {
 		"1/3 coverage - actuals != desired": {
 			//reconciler: &Reconciler{
 			metrics: &config.TestCasesMetrics{
-				ActualTestCases: map[string]bool{
-					"101": true,
-					"401": true, // not registered in desired
-					"501": true, // not registered in desired
+				ActualTestCases: map[string]config.ActualTestCase{
+					"101": {TCID: "101"},
+					"401": {TCID: "401"}, // not registered in desired
+					"501": {TCID: "501"}, // not registered in desired
 				},
-				DesiredTestCases: map[string]bool{
-					"101": true,
-					"201": true,
-					"301": true,
+				DesiredTestCases: map[string]config.PlannedTest{
+					"101": {TCID: "101"},
+					"201": {TCID: "201"},
+					"301": {TCID: "301"},
 				},
 			},
 			//},
@@ -318,18 +521,32 @@ func TestReconcilerCalculateCoverage(t *testing.T) {
 
 func TestReconcilerReconcile(t *testing.T) {
 	var tests = map[string]struct {
-		metrics *config.TestCasesMetrics
-		expect  *unstructured.Unstructured
+		configPath string
+		observed   *unstructured.Unstructured
+		metrics    *config.TestCasesMetrics
+		expect     *unstructured.Unstructured
+		isErr      bool
 	}{
 		"empty metrics": {
+			observed: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": string(types.E2EMetricsMayadataV1Alpha1),
+					"kind":       string(types.KindPipelineCoverage),
+					"metadata": map[string]interface{}{
+						"name":      "e2e",
+						"namespace": "e2e-metrics",
+						"uid":       "101",
+					},
+				},
+			},
 			metrics: &config.TestCasesMetrics{},
 			expect: &unstructured.Unstructured{
 				Object: map[string]interface{}{
 					"apiVersion": string(types.E2EMetricsMayadataV1Alpha1),
 					"kind":       string(types.KindPipelineCoverage),
 					"metadata": map[string]interface{}{
-						"name":      "",
-						"namespace": "",
+						"name":      "e2e",
+						"namespace": "e2e-metrics",
 					},
 					"spec": map[string]interface{}{
 						"pipeline": map[string]interface{}{
@@ -347,11 +564,62 @@ func TestReconcilerReconcile(t *testing.T) {
 						"runid":            "",
 						"validTestCount":   int64(0),
 						"invalidTestCount": int64(0),
+						"missingTestCount": int64(0),
 						"coverage":         "0%",
 					},
 				},
 			},
 		},
+		"sources from spec": {
+			configPath: "testdata",
+			observed:   newCoverage("gcp", "gcp"),
+			expect: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": string(types.E2EMetricsMayadataV1Alpha1),
+					"kind":       string(types.KindPipelineCoverage),
+					"metadata": map[string]interface{}{
+						"name": "gcp",
+					},
+					"spec": map[string]interface{}{
+						"pipeline": map[string]interface{}{
+							"id":    "gcp",
+							"runid": "run-gcp",
+						},
+						"plan": []interface{}{
+							map[string]interface{}{
+								"format": config.FormatMasterPlan,
+								"path":   "gcp/plan.yml",
+							},
+						},
+						"ci": []interface{}{
+							map[string]interface{}{
+								"format": config.FormatGitlabCI,
+								"path":   "gcp/ci.yml",
+							},
+						},
+						"test": map[string]interface{}{
+							"count": int64(2),
+						},
+					},
+					"result": map[string]interface{}{
+						"phase":            "Passed",
+						"reason":           "",
+						"warning":          "1 warnings: 1 invalid tests were found [TCID-GCP-RESTORE]",
+						"deprecated":       "",
+						"runid":            "run-gcp",
+						"validTestCount":   int64(1),
+						"invalidTestCount": int64(1),
+						"invalidTests":     []interface{}{"TCID-GCP-RESTORE"},
+						"missingTestCount": int64(1),
+						"missingTests":     []interface{}{"TCID-GCP-BACKUP"},
+						"coverage":         "50%",
+					},
+				},
+			},
+		},
+		"nil observed": {
+			isErr: true,
+		},
 	}
 	for name, mock := range tests {
 		name := name
@@ -360,11 +628,23 @@ func TestReconcilerReconcile(t *testing.T) {
 			log := logstesting.TestLogger{T: t}
 			prom := metrics.New(log)
 			r := NewReconciler(ReconcilerConfig{
-				Log:  log,
-				Prom: prom,
+				Log:                      log,
+				Prom:                     prom,
+				ConfigPath:               mock.configPath,
+				ObservedPipelineCoverage: mock.observed,
 			})
 			r.metrics = mock.metrics
-			got := r.Reconcile()
+			got, err := r.Reconcile()
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if got != nil {
+				// conditions are verified by TestReconcilerGetConditions
+				unstructured.RemoveNestedField(got.Object, "result", "conditions")
+			}
 			if !reflect.DeepEqual(got, mock.expect) {
 				t.Fatalf("Expected no diff got\n%s", cmp.Diff(mock.expect, got))
 			}
@@ -373,39 +653,105 @@ func TestReconcilerReconcile(t *testing.T) {
 }
 
 func TestReconcilerGetDesiredPipelineCoverage(t *testing.T) {
+	var manyTests []string
+	for i := 0; i < MaxResultTestNames+10; i++ {
+		manyTests = append(manyTests, fmt.Sprintf("TCID-%03d", i))
+	}
 	var tests = map[string]struct {
-		metrics *config.TestCasesMetrics
-		expect  *unstructured.Unstructured
+		observed     *types.PipelineCoverage
+		metrics      *config.TestCasesMetrics
+		missingTests []string
+		expect       *types.PipelineCoverage
 	}{
 		"empty metrics": {
 			metrics: &config.TestCasesMetrics{},
-			expect: &unstructured.Unstructured{
-				Object: map[string]interface{}{
-					"apiVersion": string(types.E2EMetricsMayadataV1Alpha1),
-					"kind":       string(types.KindPipelineCoverage),
-					"metadata": map[string]interface{}{
-						"name":      "",
-						"namespace": "",
-					},
-					"spec": map[string]interface{}{
-						"pipeline": map[string]interface{}{
-							"id": "",
-						},
-						"test": map[string]interface{}{
-							"count": int64(0),
-						},
-					},
-					"result": map[string]interface{}{
-						"phase":            "Passed",
-						"reason":           "",
-						"warning":          "",
-						"deprecated":       "",
-						"runid":            "",
-						"validTestCount":   int64(0),
-						"invalidTestCount": int64(0),
-						"coverage":         "0%",
+			expect: &types.PipelineCoverage{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.E2EMetricsMayadataV1Alpha1,
+					Kind:       types.KindPipelineCoverage,
+				},
+				Result: types.PipelineCoverageResult{
+					Phase:    "Passed",
+					Coverage: "0%",
+				},
+			},
+		},
+		"planned tests": {
+			metrics: &config.TestCasesMetrics{
+				DesiredTestCases: map[string]config.PlannedTest{
+					"101": {TCID: "101"},
+					"201": {TCID: "201"},
+				},
+			},
+			expect: &types.PipelineCoverage{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.E2EMetricsMayadataV1Alpha1,
+					Kind:       types.KindPipelineCoverage,
+				},
+				Spec: types.PipelineCoverageSpec{
+					Test: types.TestSpec{
+						Count: 2,
 					},
 				},
+				Result: types.PipelineCoverageResult{
+					Phase:    "Passed",
+					Coverage: "0%",
+				},
+			},
+		},
+		"observed spec is retained": {
+			observed: &types.PipelineCoverage{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gcp",
+					Namespace: "e2e-metrics",
+					Labels:    map[string]string{"app": "e2e"},
+				},
+				Spec: types.PipelineCoverageSpec{
+					Pipeline: types.PipelineSpec{ID: "gcp-101", RunID: "run-101"},
+					Plan:     []types.SourceSpec{{Format: "masterplan", Path: "plan.yml"}},
+					Test:     types.TestSpec{Count: 10},
+				},
+			},
+			metrics: &config.TestCasesMetrics{
+				DesiredTestCases: map[string]config.PlannedTest{
+					"101": {TCID: "101"},
+				},
+			},
+			expect: &types.PipelineCoverage{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.E2EMetricsMayadataV1Alpha1,
+					Kind:       types.KindPipelineCoverage,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gcp",
+					Namespace: "e2e-metrics",
+				},
+				Spec: types.PipelineCoverageSpec{
+					Pipeline: types.PipelineSpec{ID: "gcp-101", RunID: "run-101"},
+					Plan:     []types.SourceSpec{{Format: "masterplan", Path: "plan.yml"}},
+					Test:     types.TestSpec{Count: 1},
+				},
+				Result: types.PipelineCoverageResult{
+					Phase:    "Passed",
+					RunID:    "run-101",
+					Coverage: "0%",
+				},
+			},
+		},
+		"missing tests are capped": {
+			metrics:      &config.TestCasesMetrics{},
+			missingTests: manyTests,
+			expect: &types.PipelineCoverage{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: types.E2EMetricsMayadataV1Alpha1,
+					Kind:       types.KindPipelineCoverage,
+				},
+				Result: types.PipelineCoverageResult{
+					Phase:            "Passed",
+					MissingTestCount: int64(len(manyTests)),
+					MissingTests:     manyTests[:MaxResultTestNames],
+					Coverage:         "0%",
+				},
 			},
 		},
 	}
@@ -416,11 +762,487 @@ func TestReconcilerGetDesiredPipelineCoverage(t *testing.T) {
 			r := NewReconciler(ReconcilerConfig{
 				Log: logstesting.TestLogger{T: t},
 			})
+			r.observed = mock.observed
 			r.metrics = mock.metrics
+			r.missingTests = mock.missingTests
 			got := r.getDesiredPipelineCoverage()
+			// conditions are verified by TestReconcilerGetConditions
+			got.Result.Conditions = nil
 			if !reflect.DeepEqual(got, mock.expect) {
 				t.Fatalf("Expected no diff got\n%s", cmp.Diff(mock.expect, got))
 			}
 		})
 	}
 }
+
+func TestReconcilerReconcileConfigMaps(t *testing.T) {
+	configMap := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       types.KindConfigMap,
+			"metadata": map[string]interface{}{
+				"name": "gcp",
+			},
+			"data": map[string]interface{}{
+				"plan.yml": `
+kind: MasterPlan
+spec:
+  tests:
+  - tcid: TCID-GCP-UPGRADE
+  - tcid: TCID-GCP-BACKUP
+`,
+				"ci.yml": `
+TCID-GCP-UPGRADE:
+  script:
+  - ./upgrade
+`,
+			},
+		},
+	}
+	var tests = map[string]struct {
+		configPath   string
+		plan         []types.SourceSpec
+		ci           []types.SourceSpec
+		expectResult types.PipelineCoverageResult
+	}{
+		"keys of config map without config directory": {
+			configPath: "testdata/missing",
+			plan: []types.SourceSpec{
+				{Format: "masterplan", ConfigMap: &types.ConfigMapSourceSpec{Name: "gcp", Key: "plan.yml"}},
+			},
+			ci: []types.SourceSpec{
+				{Format: "gitlabci", ConfigMap: &types.ConfigMapSourceSpec{Name: "gcp", Key: "ci.yml"}},
+			},
+			expectResult: types.PipelineCoverageResult{
+				Phase:            "Passed",
+				ValidTestCount:   1,
+				MissingTestCount: 1,
+				MissingTests:     []string{"TCID-GCP-BACKUP"},
+				Coverage:         "50%",
+			},
+		},
+		"config map with local files": {
+			configPath: "testdata",
+			plan: []types.SourceSpec{
+				{Format: "masterplan", ConfigMap: &types.ConfigMapSourceSpec{Name: "gcp"}, Path: "plan*"},
+			},
+			ci: []types.SourceSpec{
+				{Format: "gitlabci", Path: "gcp/ci.yml"},
+			},
+			expectResult: types.PipelineCoverageResult{
+				Phase:            "Passed",
+				Warning:          "1 warnings: 1 invalid tests were found [TCID-GCP-RESTORE]",
+				ValidTestCount:   1,
+				InvalidTestCount: 1,
+				InvalidTests:     []string{"TCID-GCP-RESTORE"},
+				MissingTestCount: 1,
+				MissingTests:     []string{"TCID-GCP-BACKUP"},
+				Coverage:         "50%",
+			},
+		},
+		"missing config map": {
+			configPath: "testdata",
+			plan: []types.SourceSpec{
+				{Format: "masterplan", ConfigMap: &types.ConfigMapSourceSpec{Name: "aws"}},
+			},
+			expectResult: types.PipelineCoverageResult{
+				Phase:    "Failed",
+				Reason:   `ConfigMap "aws" not found`,
+				Coverage: "0%",
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			observed, err := (&types.PipelineCoverage{
+				ObjectMeta: metav1.ObjectMeta{Name: "gcp"},
+				Spec: types.PipelineCoverageSpec{
+					Plan: mock.plan,
+					CI:   mock.ci,
+				},
+			}).ToUnstructured()
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			log := logstesting.TestLogger{T: t}
+			r := NewReconciler(ReconcilerConfig{
+				Log:                      log,
+				Prom:                     metrics.New(log),
+				ConfigPath:               mock.configPath,
+				ObservedPipelineCoverage: observed,
+				ConfigMaps: map[string]*unstructured.Unstructured{
+					"gcp": configMap,
+				},
+			})
+			got, err := r.Reconcile()
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			coverage, err := types.PipelineCoverageFromUnstructured(got)
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			// conditions are verified by TestReconcilerGetConditions
+			coverage.Result.Conditions = nil
+			if !reflect.DeepEqual(coverage.Result, mock.expectResult) {
+				t.Fatalf(
+					"Expected no diff got\n%s",
+					cmp.Diff(mock.expectResult, coverage.Result),
+				)
+			}
+		})
+	}
+}
+
+func TestSyncableResync(t *testing.T) {
+	dir, err := ioutil.TempDir("", "e2e-metrics-resync")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	writeFile := func(name, content string) {
+		err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755)
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+	}
+	writeFile("gcp/plan.yml", `
+kind: MasterPlan
+spec:
+  tests:
+  - tcid: TCID-GCP-UPGRADE
+  - tcid: TCID-GCP-BACKUP
+`)
+	writeFile("gcp/ci.yml", `
+TCID-GCP-UPGRADE:
+  script:
+  - ./upgrade
+`)
+
+	log := &logstesting.TestLogger{T: t}
+	m := metrics.New(log)
+	recorder := record.NewFakeRecorder(10)
+	s := NewSyncer(SyncerConfig{
+		Log:        log,
+		Prom:       m,
+		ConfigPath: dir,
+		Recorder:   recorder,
+	})
+	expectCoverageRatio := func(expect string) {
+		err := testutil.CollectAndCompare(m.CoverageRatio, strings.NewReader(`
+# HELP e2emet_coverage_ratio Ratio of planned test cases that are implemented.
+# TYPE e2emet_coverage_ratio gauge
+`+expect))
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+	}
+	// nothing is observed before the first sync
+	s.Resync()
+	expectCoverageRatio("")
+	response := &generic.SyncHookResponse{}
+	err = s.Sync(
+		&generic.SyncHookRequest{
+			Watch: &unstructured.Unstructured{
+				Object: map[string]interface{}{},
+			},
+			Attachments: newAttachments(newCoverage("gcp", "gcp")),
+		},
+		response,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	expectCoverage := func(attachments []*unstructured.Unstructured, expect string) {
+		if len(attachments) != 1 {
+			t.Fatalf("Expected 1 coverage got %d", len(attachments))
+		}
+		got, _, _ := unstructured.NestedString(
+			attachments[0].Object, "result", "coverage",
+		)
+		if got != expect {
+			t.Fatalf("Expected coverage %s got %s", expect, got)
+		}
+	}
+	expectCoverage(response.Attachments, "50%")
+	expectCoverageRatio(`e2emet_coverage_ratio{pipelinecoverage="gcp",pipelineid="gcp"} 0.5` + "\n")
+	recorded := len(recorder.Events)
+
+	writeFile("gcp/ci.yml", `
+TCID-GCP-UPGRADE:
+  script:
+  - ./upgrade
+TCID-GCP-BACKUP:
+  script:
+  - ./backup
+`)
+	s.Resync()
+	expectCoverageRatio(`e2emet_coverage_ratio{pipelinecoverage="gcp",pipelineid="gcp"} 1` + "\n")
+	// events are recorded by syncs only
+	if len(recorder.Events) != recorded {
+		t.Fatalf("Expected %d events got %d", recorded, len(recorder.Events))
+	}
+}
+
+func TestReconcilerReconcileGit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "e2e-metrics-git")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	repo, err := git.PlainInit(filepath.Join(dir, "e2e"), false)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	err = ioutil.WriteFile(
+		filepath.Join(dir, "e2e", "plan.yml"),
+		[]byte("kind: MasterPlan\nspec:\n  tests:\n  - tcid: TCID-GCP-UPGRADE\n"),
+		0644,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	tree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	_, err = tree.Add("plan.yml")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	commit, err := tree.Commit("plan", &git.CommitOptions{
+		Author: &object.Signature{Name: "e2e", Email: "e2e@mayadata.io"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+
+	observed, err := (&types.PipelineCoverage{
+		ObjectMeta: metav1.ObjectMeta{Name: "gcp"},
+		Spec: types.PipelineCoverageSpec{
+			Git:  &types.GitSpec{Path: "e2e"},
+			Plan: []types.SourceSpec{{Format: "masterplan", Path: "plan.yml"}},
+			CI:   []types.SourceSpec{{Format: "gitlabci", Path: "ci.yml", Optional: true}},
+		},
+	}).ToUnstructured()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	log := logstesting.TestLogger{T: t}
+	r := NewReconciler(ReconcilerConfig{
+		Log:                      log,
+		Prom:                     metrics.New(log),
+		ConfigPath:               dir,
+		ObservedPipelineCoverage: observed,
+	})
+	got, err := r.Reconcile()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	coverage, err := types.PipelineCoverageFromUnstructured(got)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if coverage.Result.Phase != types.PipelineCoveragePassed {
+		t.Fatalf("Expected phase Passed got %+v", coverage.Result)
+	}
+	if coverage.Result.Commit != commit.String() {
+		t.Fatalf("Expected commit %q got %q", commit, coverage.Result.Commit)
+	}
+	if coverage.Spec.Test.Count != 1 {
+		t.Fatalf("Expected 1 planned test got %d", coverage.Spec.Test.Count)
+	}
+}
+
+func TestReconcilerReconcileReports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "e2e-metrics-report")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	observed, err := (&types.PipelineCoverage{
+		ObjectMeta: metav1.ObjectMeta{Name: "gcp"},
+		Spec: types.PipelineCoverageSpec{
+			Pipeline: types.PipelineSpec{ID: "101", RunID: "7"},
+			Plan:     []types.SourceSpec{{Format: "masterplan", Path: "gcp/plan.yml"}},
+			CI:       []types.SourceSpec{{Format: "gitlabci", Path: "gcp/ci.yml"}},
+		},
+	}).ToUnstructured()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	log := logstesting.TestLogger{T: t}
+	r := NewReconciler(ReconcilerConfig{
+		Log:                      log,
+		Prom:                     metrics.New(log),
+		ConfigPath:               "testdata",
+		ObservedPipelineCoverage: observed,
+		ReportDir:                dir,
+		ReportFormats:            []string{report.FormatJSON, report.FormatJUnit},
+	})
+	_, err = r.Reconcile()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "gcp.json"))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	var got report.Report
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	expect := report.Report{
+		Name:             "gcp",
+		PipelineID:       "101",
+		RunID:            "7",
+		Coverage:         .5,
+		PlannedTestCount: 2,
+		ValidTests:       []string{"TCID-GCP-UPGRADE"},
+		InvalidTests:     []string{"TCID-GCP-RESTORE"},
+		MissingTests:     []string{"TCID-GCP-BACKUP"},
+		DeprecatedTests:  []string{},
+		DuplicateTests:   []string{},
+		Warnings:         []string{"1 invalid tests were found [TCID-GCP-RESTORE]"},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Fatalf("Expected no diff got\n%s", cmp.Diff(expect, got))
+	}
+	_, err = os.Stat(filepath.Join(dir, "gcp.xml"))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+}
+
+func TestSyncableReconcileAllMetrics(t *testing.T) {
+	newCoverage := func(name, pipelineID string) *unstructured.Unstructured {
+		coverage, err := (&types.PipelineCoverage{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: types.PipelineCoverageSpec{
+				Pipeline: types.PipelineSpec{ID: pipelineID},
+				Plan:     []types.SourceSpec{{Format: "masterplan", Path: "gcp/plan.yml"}},
+				CI:       []types.SourceSpec{{Format: "gitlabci", Path: "gcp/ci.yml"}},
+			},
+		}).ToUnstructured()
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+		return coverage
+	}
+	log := &logstesting.TestLogger{T: t}
+	m := metrics.New(log)
+	s := NewSyncer(SyncerConfig{
+		Log:        log,
+		Prom:       m,
+		ConfigPath: "testdata",
+	})
+
+	s.syncAll(
+		[]*unstructured.Unstructured{
+			newCoverage("gcp", "101"),
+			newCoverage("aws", "201"),
+		},
+		nil,
+	)
+	err := testutil.CollectAndCompare(m.CoverageRatio, strings.NewReader(`
+# HELP e2emet_coverage_ratio Ratio of planned test cases that are implemented.
+# TYPE e2emet_coverage_ratio gauge
+e2emet_coverage_ratio{pipelinecoverage="aws",pipelineid="201"} 0.5
+e2emet_coverage_ratio{pipelinecoverage="gcp",pipelineid="101"} 0.5
+`))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+
+	// aws is deleted & pipeline id of gcp is changed
+	s.syncAll([]*unstructured.Unstructured{newCoverage("gcp", "102")}, nil)
+	for _, gauge := range []struct {
+		collector prometheus.Collector
+		expect    string
+	}{
+		{m.CoverageRatio, `
+# HELP e2emet_coverage_ratio Ratio of planned test cases that are implemented.
+# TYPE e2emet_coverage_ratio gauge
+e2emet_coverage_ratio{pipelinecoverage="gcp",pipelineid="102"} 0.5
+`},
+		{m.ValidTestsTotal, `
+# HELP e2emet_valid_test_count Total number of implemented test cases that are planned.
+# TYPE e2emet_valid_test_count gauge
+e2emet_valid_test_count{pipelinecoverage="gcp",pipelineid="102"} 1
+`},
+		{m.InvalidTestsTotal, `
+# HELP e2emet_invalid_test_count Total number of implemented test cases that are not planned.
+# TYPE e2emet_invalid_test_count gauge
+e2emet_invalid_test_count{pipelinecoverage="gcp",pipelineid="102"} 1
+`},
+		{m.MissingTestsTotal, `
+# HELP e2emet_missing_test_count Total number of planned test cases that are not implemented.
+# TYPE e2emet_missing_test_count gauge
+e2emet_missing_test_count{pipelinecoverage="gcp",pipelineid="102"} 1
+`},
+		{m.DeprecatedTestsTotal, `
+# HELP e2emet_deprecated_test_count Total number of test cases with deprecated tcid- prefix.
+# TYPE e2emet_deprecated_test_count gauge
+e2emet_deprecated_test_count{pipelinecoverage="gcp",pipelineid="102"} 0
+`},
+		{m.TestCaseInfo, `
+# HELP e2emet_test_case_info Test cases with their states. Value is always 1.
+# TYPE e2emet_test_case_info gauge
+e2emet_test_case_info{group="",impl="litmus",pipelinecoverage="gcp",state="invalid",tcid="TCID-GCP-RESTORE",type=""} 1
+e2emet_test_case_info{group="dmaas",impl="",pipelinecoverage="gcp",state="missing",tcid="TCID-GCP-BACKUP",type=""} 1
+e2emet_test_case_info{group="upgrade",impl="litmus",pipelinecoverage="gcp",state="valid",tcid="TCID-GCP-UPGRADE",type="functional"} 1
+`},
+		{m.PlannedTestsTotal, `
+# HELP e2emet_planned_test_count Total number of planned test cases.
+# TYPE e2emet_planned_test_count gauge
+e2emet_planned_test_count{component="",feature="",kind="",pipelinecoverage="gcp",testimpltype="litmus"} 2
+`},
+		{m.ActualTestsTotal, `
+# HELP e2emet_actual_test_count Total number of actual test cases.
+# TYPE e2emet_actual_test_count gauge
+e2emet_actual_test_count{component="",feature="",kind="",pipelinecoverage="gcp",testimpltype="litmus"} 2
+`},
+	} {
+		err = testutil.CollectAndCompare(gauge.collector, strings.NewReader(gauge.expect))
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+	}
+
+	// config of gcp fails to load
+	failing, err := (&types.PipelineCoverage{
+		ObjectMeta: metav1.ObjectMeta{Name: "gcp"},
+		Spec: types.PipelineCoverageSpec{
+			Pipeline: types.PipelineSpec{ID: "102"},
+			Plan:     []types.SourceSpec{{Format: "masterplan", Path: "gcp/missing.yml"}},
+			CI:       []types.SourceSpec{{Format: "gitlabci", Path: "gcp/ci.yml"}},
+		},
+	}).ToUnstructured()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	s.syncAll([]*unstructured.Unstructured{failing}, nil)
+	for _, collector := range []prometheus.Collector{
+		m.CoverageRatio,
+		m.ValidTestsTotal,
+		m.InvalidTestsTotal,
+		m.MissingTestsTotal,
+		m.DeprecatedTestsTotal,
+		m.TestCaseInfo,
+		m.PlannedTestsTotal,
+		m.ActualTestsTotal,
+	} {
+		err = testutil.CollectAndCompare(collector, strings.NewReader(""))
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+	}
+}
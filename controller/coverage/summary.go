@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"mayadata.io/e2e-metrics/report"
+)
+
+// Summary has the test cases of a calculated coverage
+type Summary struct {
+	// ValidTests are implemented & planned
+	ValidTests []string
+
+	// InvalidTests are implemented but not planned
+	InvalidTests []string
+
+	// MissingTests are planned but not implemented
+	MissingTests []string
+
+	// DeprecatedTests are implemented with the deprecated tcid-
+	// prefix
+	DeprecatedTests []string
+
+	// DuplicateTests are either planned more than once or are
+	// implemented by more than one job
+	DuplicateTests []string
+
+	PlannedTestCount int
+	Coverage         Percentage
+
+	// Commit is the SHA of the git commit that the tests are loaded
+	// from if any
+	Commit   string
+	Warnings []string
+}
+
+// sortedCopy returns a sorted copy of the given list
+func sortedCopy(list []string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	sorted := append([]string(nil), list...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// coverageRatio returns the ratio of the given valid to planned
+// test counts
+//
+// NOTE:
+//	This avoids the rounding errors of float32 Percentage
+func coverageRatio(valid, planned int) float64 {
+	if planned == 0 {
+		return 0
+	}
+	return float64(valid) / float64(planned)
+}
+
+// Report returns the given summary as a report that can be rendered
+// in any of the report formats
+func (s *Summary) Report() *report.Report {
+	return &report.Report{
+		Commit:           s.Commit,
+		Coverage:         coverageRatio(len(s.ValidTests), s.PlannedTestCount),
+		PlannedTestCount: s.PlannedTestCount,
+		ValidTests:       s.ValidTests,
+		InvalidTests:     s.InvalidTests,
+		MissingTests:     s.MissingTests,
+		DeprecatedTests:  s.DeprecatedTests,
+		DuplicateTests:   s.DuplicateTests,
+		Warnings:         s.Warnings,
+	}
+}
+
+// WriteText writes the given summary in a human readable form
+func (s *Summary) WriteText(w io.Writer) error {
+	var sections = []struct {
+		title string
+		tests []string
+	}{
+		{"Valid", s.ValidTests},
+		{"Invalid", s.InvalidTests},
+		{"Missing", s.MissingTests},
+		{"Deprecated", s.DeprecatedTests},
+		{"Duplicate", s.DuplicateTests},
+	}
+	for _, section := range sections {
+		_, err := fmt.Fprintf(w, "%s tests: %d\n", section.title, len(section.tests))
+		if err != nil {
+			return err
+		}
+		for _, tcid := range section.tests {
+			_, err = fmt.Fprintf(w, "  %s\n", tcid)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if s.Commit != "" {
+		_, err := fmt.Fprintf(w, "Commit: %s\n", s.Commit)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(
+		w,
+		"Coverage: %s (%d/%d)\n",
+		s.Coverage,
+		len(s.ValidTests),
+		s.PlannedTestCount,
+	)
+	return err
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coverage
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"mayadata.io/e2e-metrics/config"
+	"mayadata.io/e2e-metrics/metrics"
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+)
+
+func TestReconcilerCalculate(t *testing.T) {
+	var tests = map[string]struct {
+		desiredSources []config.SourceConfig
+		actualSources  []config.SourceConfig
+		taxonomy       *config.Taxonomy
+		expect         *Summary
+		isErr          bool
+	}{
+		"gcp": {
+			desiredSources: []config.SourceConfig{
+				{Format: config.FormatMasterPlan, Path: "gcp/plan.yml"},
+			},
+			actualSources: []config.SourceConfig{
+				{Format: config.FormatGitlabCI, Path: "gcp/ci.yml"},
+			},
+			expect: &Summary{
+				ValidTests:       []string{"TCID-GCP-UPGRADE"},
+				InvalidTests:     []string{"TCID-GCP-RESTORE"},
+				MissingTests:     []string{"TCID-GCP-BACKUP"},
+				PlannedTestCount: 2,
+				Coverage:         .5,
+				Warnings: []string{
+					"1 invalid tests were found [TCID-GCP-RESTORE]",
+				},
+			},
+		},
+		"undeclared implementation type": {
+			desiredSources: []config.SourceConfig{
+				{Format: config.FormatMasterPlan, Path: "gcp/plan.yml"},
+			},
+			actualSources: []config.SourceConfig{
+				{Format: config.FormatGitlabCI, Path: "gcp/ci.yml"},
+			},
+			taxonomy: &config.Taxonomy{
+				Dimensions: []config.Dimension{
+					{Name: "component", LabelKey: "test/component"},
+				},
+				ImplementationTypes: []metrics.TestImplementationType{
+					metrics.TestImplementationTypeDope,
+				},
+			},
+			expect: &Summary{
+				ValidTests:       []string{"TCID-GCP-UPGRADE"},
+				InvalidTests:     []string{"TCID-GCP-RESTORE"},
+				MissingTests:     []string{"TCID-GCP-BACKUP"},
+				PlannedTestCount: 2,
+				Coverage:         .5,
+				Warnings: []string{
+					"1 invalid tests were found [TCID-GCP-RESTORE]",
+					"2 undeclared values were found [" +
+						`TCID-GCP-RESTORE has undeclared testimpltype "litmus", ` +
+						`TCID-GCP-UPGRADE has undeclared testimpltype "litmus"]`,
+				},
+			},
+		},
+		"missing source": {
+			desiredSources: []config.SourceConfig{
+				{Format: config.FormatMasterPlan, Path: "missing.yml"},
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			log := logstesting.TestLogger{T: t}
+			r := NewReconciler(ReconcilerConfig{
+				Log:            log,
+				Prom:           metrics.New(log),
+				ConfigPath:     "testdata",
+				DesiredSources: mock.desiredSources,
+				ActualSources:  mock.actualSources,
+				Taxonomy:       mock.taxonomy,
+			})
+			got, err := r.Calculate()
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(got, mock.expect) {
+				t.Fatalf("Expected no diff got\n%s", cmp.Diff(mock.expect, got))
+			}
+		})
+	}
+}
+
+func TestSummaryWriteText(t *testing.T) {
+	summary := &Summary{
+		ValidTests:       []string{"TCID-A"},
+		InvalidTests:     []string{"TCID-X", "TCID-Y"},
+		MissingTests:     []string{"TCID-B"},
+		DuplicateTests:   []string{"TCID-X"},
+		PlannedTestCount: 2,
+		Coverage:         .5,
+		Commit:           "abc",
+	}
+	var expect = `Valid tests: 1
+  TCID-A
+Invalid tests: 2
+  TCID-X
+  TCID-Y
+Missing tests: 1
+  TCID-B
+Deprecated tests: 0
+Duplicate tests: 1
+  TCID-X
+Commit: abc
+Coverage: 50% (1/2)
+`
+	var got bytes.Buffer
+	err := summary.WriteText(&got)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if got.String() != expect {
+		t.Fatalf("Expected no diff got\n%s", cmp.Diff(expect, got.String()))
+	}
+}
@@ -29,12 +29,14 @@ limitations under the License.
 // Run the following kubectl commands in the Kubernetes setup in the
 // following order to **test** this controller
 //
+// # crd.yaml is generated from the API types via make manifests
 // kubectl apply -f crd.yaml
 // kubectl apply -f namespace.yaml
 // kubectl apply -f rbac.yaml
 // kubectl create configmap metac-config-test -n e2e-metrics --from-file=metac-config.yaml
 // kubectl create configmap metrics-config-test -n e2e-metrics --from-file=testing/.master-plan.yml --from-file=testing/.gitlab-ci.yml
 // kubectl apply -f testing/test-operator.yaml
+// kubectl apply -f testing/pipeline-coverage.yaml
 // kubectl apply -f service.yaml
 //
 // # Setup prometheus & grafana
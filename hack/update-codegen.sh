@@ -0,0 +1,49 @@
+#!/usr/bin/env bash
+
+# Copyright 2020 The MayaData Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     https://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# This generates the deepcopy functions of the API types
+#
+# NOTE:
+#	deepcopy-gen is installed if it is not found in PATH
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+ROOT=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+MODULE=mayadata.io/e2e-metrics
+CODEGEN_VERSION=${CODEGEN_VERSION:-v0.17.3}
+
+DEEPCOPY_GEN=$(command -v deepcopy-gen || true)
+if [[ -z "${DEEPCOPY_GEN}" ]]; then
+  GOBIN="${ROOT}/bin" GO111MODULE=on \
+    go install "k8s.io/code-generator/cmd/deepcopy-gen@${CODEGEN_VERSION}"
+  DEEPCOPY_GEN="${ROOT}/bin/deepcopy-gen"
+fi
+
+# deepcopy-gen writes the generated files relative to the output
+# base & hence a temporary GOPATH like layout is used
+OUTPUT_BASE=$(mktemp -d)
+trap 'rm -rf "${OUTPUT_BASE}"' EXIT
+mkdir -p "$(dirname "${OUTPUT_BASE}/${MODULE}")"
+ln -s "${ROOT}" "${OUTPUT_BASE}/${MODULE}"
+
+cd "${OUTPUT_BASE}/${MODULE}"
+"${DEEPCOPY_GEN}" \
+  --input-dirs "${MODULE}/types" \
+  --output-file-base zz_generated.deepcopy \
+  --output-base "${OUTPUT_BASE}" \
+  --go-header-file "${ROOT}/hack/boilerplate.go.txt"
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	CoverageRatioMetricName string = "coverage_ratio"
+
+	CoverageRatioMetricHelp string = "Ratio of planned test cases that are implemented."
+
+	ValidTestCountMetricName string = "valid_test_count"
+
+	ValidTestCountMetricHelp string = "Total number of implemented test cases that are planned."
+
+	InvalidTestCountMetricName string = "invalid_test_count"
+
+	InvalidTestCountMetricHelp string = "Total number of implemented test cases that are not planned."
+
+	MissingTestCountMetricName string = "missing_test_count"
+
+	MissingTestCountMetricHelp string = "Total number of planned test cases that are not implemented."
+
+	DeprecatedTestCountMetricName string = "deprecated_test_count"
+
+	DeprecatedTestCountMetricHelp string = "Total number of test cases with deprecated tcid- prefix."
+)
+
+var (
+	// CoverageMetricLblNames are the labels of the metrics that are
+	// set per PipelineCoverage
+	CoverageMetricLblNames = []string{"pipelineid", "pipelinecoverage"}
+)
+
+// PipelineCoverage structure to populate metrics
+//
+// It exposes following metrics:
+// 	coverage_ratio{"pipelineid", "pipelinecoverage"}
+// 	valid_test_count{"pipelineid", "pipelinecoverage"}
+// 	invalid_test_count{"pipelineid", "pipelinecoverage"}
+// 	missing_test_count{"pipelineid", "pipelinecoverage"}
+// 	deprecated_test_count{"pipelineid", "pipelinecoverage"}
+// where
+// - pipelineid is the id of the pipeline
+// - pipelinecoverage is the name of the PipelineCoverage
+type PipelineCoverage struct {
+	PipelineID string
+	Name       string
+
+	// Coverage is a ratio e.g. 0.5 for 50%
+	Coverage float64
+
+	ValidTestCount      float64
+	InvalidTestCount    float64
+	MissingTestCount    float64
+	DeprecatedTestCount float64
+}
+
+// labels returns the prometheus labels of the given coverage
+func (pc *PipelineCoverage) labels() prometheus.Labels {
+	return prometheus.Labels{
+		"pipelineid":       pc.PipelineID,
+		"pipelinecoverage": pc.Name,
+	}
+}
+
+// coverageGauges returns the gauges that are set per
+// PipelineCoverage
+func (m *Metrics) coverageGauges() []*prometheus.GaugeVec {
+	return []*prometheus.GaugeVec{
+		m.CoverageRatio,
+		m.ValidTestsTotal,
+		m.InvalidTestsTotal,
+		m.MissingTestsTotal,
+		m.DeprecatedTestsTotal,
+	}
+}
+
+// SetPipelineCoverage sets the coverage metrics of the given
+// PipelineCoverage
+func (m *Metrics) SetPipelineCoverage(pc *PipelineCoverage) {
+	labels := pc.labels()
+	m.CoverageRatio.With(labels).Set(pc.Coverage)
+	m.ValidTestsTotal.With(labels).Set(pc.ValidTestCount)
+	m.InvalidTestsTotal.With(labels).Set(pc.InvalidTestCount)
+	m.MissingTestsTotal.With(labels).Set(pc.MissingTestCount)
+	m.DeprecatedTestsTotal.With(labels).Set(pc.DeprecatedTestCount)
+}
+
+// DeletePipelineCoverage removes the coverage metrics of the
+// given PipelineCoverage. This is meant to be invoked when the
+// PipelineCoverage is deleted or its pipeline id changes.
+func (m *Metrics) DeletePipelineCoverage(pipelineID, name string) {
+	labels := (&PipelineCoverage{PipelineID: pipelineID, Name: name}).labels()
+	for _, gauge := range m.coverageGauges() {
+		gauge.Delete(labels)
+	}
+}
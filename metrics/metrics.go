@@ -23,6 +23,7 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -50,14 +51,62 @@ type Metrics struct {
 	GitlabCIYMLLoadDurationSeconds   *prometheus.SummaryVec
 	MasterPlanYMLLoadDurationSeconds *prometheus.SummaryVec
 
+	SourceLoadDurationSeconds         *prometheus.HistogramVec
+	SourceLastSuccessfulLoadTimestamp *prometheus.GaugeVec
+	SourceParsedBytesTotal            *prometheus.CounterVec
+
 	PlannedTestsTotal *prometheus.GaugeVec
 	ActualTestsTotal  *prometheus.GaugeVec
 
+	CoverageRatio        *prometheus.GaugeVec
+	ValidTestsTotal      *prometheus.GaugeVec
+	InvalidTestsTotal    *prometheus.GaugeVec
+	MissingTestsTotal    *prometheus.GaugeVec
+	DeprecatedTestsTotal *prometheus.GaugeVec
+
+	TestCaseInfo *prometheus.GaugeVec
+
 	ControllerSyncCallCount *prometheus.CounterVec
+
+	// test count series that are set per PipelineCoverage. These
+	// are used to remove stale series.
+	testCountLock          sync.Mutex
+	plannedTestCountSeries testCountSeries
+	actualTestCountSeries  testCountSeries
+
+	// testCountDimensions are the label names of test count metrics
+	// other than the test implementation type
+	testCountDimensions []string
+
+	// test case infos that are set per group. These are used to
+	// remove stale series.
+	testCaseInfoLock sync.Mutex
+	testCaseInfos    map[string]map[TestCaseInfo]bool
 }
 
-// New returns a new instance of Metrics
+// Config is used to create a new instance of Metrics
+type Config struct {
+	Log logr.Logger
+
+	// TestCountDimensions are the label names of test count metrics
+	// other than the test implementation type. These default to
+	// DefaultTestCountDimensions.
+	TestCountDimensions []string
+}
+
+// New returns a new instance of Metrics with the default test count
+// dimensions
 func New(log logr.Logger) *Metrics {
+	return NewFromConfig(Config{Log: log})
+}
+
+// NewFromConfig returns a new instance of Metrics based on the given
+// config
+func NewFromConfig(conf Config) *Metrics {
+	testCountDimensions := conf.TestCountDimensions
+	if len(testCountDimensions) == 0 {
+		testCountDimensions = DefaultTestCountDimensions
+	}
 	var (
 		GitlabCIYMLLoadDurationSeconds = prometheus.NewSummaryVec(
 			prometheus.SummaryOpts{
@@ -79,13 +128,41 @@ func New(log logr.Logger) *Metrics {
 			MasterPlanYMLLoadDurationSecondsMetricLblNames,
 		)
 
+		SourceLoadDurationSeconds = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      SourceLoadDurationSecondsMetricName,
+				Help:      SourceLoadDurationSecondsMetricHelp,
+				Buckets:   SourceLoadDurationSecondsMetricBuckets,
+			},
+			SourceLoadDurationSecondsMetricLblNames,
+		)
+
+		SourceLastSuccessfulLoadTimestamp = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      SourceLastSuccessfulLoadMetricName,
+				Help:      SourceLastSuccessfulLoadMetricHelp,
+			},
+			SourceLoadMetricLblNames,
+		)
+
+		SourceParsedBytesTotal = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      SourceParsedBytesMetricName,
+				Help:      SourceParsedBytesMetricHelp,
+			},
+			SourceLoadMetricLblNames,
+		)
+
 		PlannedTestCount = prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      PlannedTestCountMetricName,
 				Help:      PlannedTestCountMetricHelp,
 			},
-			TestCountMetricLblNames,
+			TestCountMetricLblNames(testCountDimensions),
 		)
 
 		ActualTestCount = prometheus.NewGaugeVec(
@@ -94,7 +171,61 @@ func New(log logr.Logger) *Metrics {
 				Name:      ActualTestCountMetricName,
 				Help:      ActualTestCountMetricHelp,
 			},
-			TestCountMetricLblNames,
+			TestCountMetricLblNames(testCountDimensions),
+		)
+
+		CoverageRatio = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      CoverageRatioMetricName,
+				Help:      CoverageRatioMetricHelp,
+			},
+			CoverageMetricLblNames,
+		)
+
+		ValidTestCount = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      ValidTestCountMetricName,
+				Help:      ValidTestCountMetricHelp,
+			},
+			CoverageMetricLblNames,
+		)
+
+		InvalidTestCount = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      InvalidTestCountMetricName,
+				Help:      InvalidTestCountMetricHelp,
+			},
+			CoverageMetricLblNames,
+		)
+
+		MissingTestCount = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      MissingTestCountMetricName,
+				Help:      MissingTestCountMetricHelp,
+			},
+			CoverageMetricLblNames,
+		)
+
+		DeprecatedTestCount = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      DeprecatedTestCountMetricName,
+				Help:      DeprecatedTestCountMetricHelp,
+			},
+			CoverageMetricLblNames,
+		)
+
+		TestCaseInfoVec = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      TestCaseInfoMetricName,
+				Help:      TestCaseInfoMetricHelp,
+			},
+			TestCaseInfoMetricLblNames,
 		)
 
 		controllerSyncCallCount = prometheus.NewCounterVec(
@@ -109,14 +240,29 @@ func New(log logr.Logger) *Metrics {
 
 	// Create server and register Prometheus metrics handler
 	m := &Metrics{
-		log:      log.WithName("metrics"),
+		log:      conf.Log.WithName("metrics"),
 		registry: prometheus.NewRegistry(),
 
 		MasterPlanYMLLoadDurationSeconds: MasterPlanYMLLoadDurationSeconds,
 		GitlabCIYMLLoadDurationSeconds:   GitlabCIYMLLoadDurationSeconds,
-		ActualTestsTotal:                 ActualTestCount,
-		PlannedTestsTotal:                PlannedTestCount,
-		ControllerSyncCallCount:          controllerSyncCallCount,
+
+		SourceLoadDurationSeconds:         SourceLoadDurationSeconds,
+		SourceLastSuccessfulLoadTimestamp: SourceLastSuccessfulLoadTimestamp,
+		SourceParsedBytesTotal:            SourceParsedBytesTotal,
+
+		ActualTestsTotal:        ActualTestCount,
+		PlannedTestsTotal:       PlannedTestCount,
+		CoverageRatio:           CoverageRatio,
+		ValidTestsTotal:         ValidTestCount,
+		InvalidTestsTotal:       InvalidTestCount,
+		MissingTestsTotal:       MissingTestCount,
+		DeprecatedTestsTotal:    DeprecatedTestCount,
+		TestCaseInfo:            TestCaseInfoVec,
+		testCaseInfos:           map[string]map[TestCaseInfo]bool{},
+		ControllerSyncCallCount: controllerSyncCallCount,
+		testCountDimensions:     testCountDimensions,
+		plannedTestCountSeries:  testCountSeries{},
+		actualTestCountSeries:   testCountSeries{},
 	}
 
 	return m
@@ -127,8 +273,17 @@ func (m *Metrics) Start(listenAddress string) (*http.Server, error) {
 	m.registry.MustRegister(
 		m.ActualTestsTotal,
 		m.PlannedTestsTotal,
+		m.CoverageRatio,
+		m.ValidTestsTotal,
+		m.InvalidTestsTotal,
+		m.MissingTestsTotal,
+		m.DeprecatedTestsTotal,
+		m.TestCaseInfo,
 		m.GitlabCIYMLLoadDurationSeconds,
 		m.MasterPlanYMLLoadDurationSeconds,
+		m.SourceLoadDurationSeconds,
+		m.SourceLastSuccessfulLoadTimestamp,
+		m.SourceParsedBytesTotal,
 		m.ControllerSyncCallCount,
 	)
 
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type SourceLoadStatus string
+
+const (
+	SourceLoadStatusFailed SourceLoadStatus = "failed"
+
+	SourceLoadStatusPassed SourceLoadStatus = "passed"
+)
+
+const (
+	SourceLoadDurationSecondsMetricName string = "source_load_duration_seconds"
+
+	SourceLoadDurationSecondsMetricHelp string = "Time taken in seconds to load & parse a test source."
+
+	SourceLastSuccessfulLoadMetricName string = "source_last_successful_load_timestamp_seconds"
+
+	SourceLastSuccessfulLoadMetricHelp string = "Unix time in seconds of the last successful load of a test source."
+
+	SourceParsedBytesMetricName string = "source_parsed_bytes_total"
+
+	SourceParsedBytesMetricHelp string = "Total number of bytes read while parsing a test source."
+)
+
+var (
+	SourceLoadDurationSecondsMetricLblNames = []string{"namespace", "pipelinecoverage", "kind", "file", "status"}
+
+	// SourceLoadDurationSecondsMetricBuckets range from 1ms to ~16s
+	SourceLoadDurationSecondsMetricBuckets = prometheus.ExponentialBuckets(0.001, 2, 15)
+
+	SourceLoadMetricLblNames = []string{"namespace", "pipelinecoverage", "kind", "file"}
+)
+
+// SourceLoad structure to populate metrics
+//
+// It exposes following metrics:
+// 	source_load_duration_seconds{"namespace", "pipelinecoverage", "kind", "file", "status"}
+// 	source_last_successful_load_timestamp_seconds{"namespace", "pipelinecoverage", "kind", "file"}
+// 	source_parsed_bytes_total{"namespace", "pipelinecoverage", "kind", "file"}
+// where
+// - namespace & pipelinecoverage identify the PipelineCoverage that
+//   loads the source
+// - kind is the format of the source e.g. masterplan, gitlabci
+// - file is the path of the source. It can be a glob pattern.
+// - status="passed|failed"
+type SourceLoad struct {
+	Namespace        string
+	PipelineCoverage string
+
+	Kind   string
+	File   string
+	Status SourceLoadStatus
+
+	DurationInSeconds float64
+
+	// ParsedBytes is the size of all the files read by the source
+	// including the included files
+	ParsedBytes float64
+
+	// Time when this load completed
+	Time time.Time
+}
+
+// ObserveSourceLoad observes the load of a test source
+//
+// NOTE:
+//	Bytes are counted even if the load fails. Timestamp is set only
+// if the load passes.
+func (m *Metrics) ObserveSourceLoad(load *SourceLoad) {
+	m.SourceLoadDurationSeconds.
+		With(
+			prometheus.Labels{
+				"namespace":        load.Namespace,
+				"pipelinecoverage": load.PipelineCoverage,
+				"kind":             load.Kind,
+				"file":             load.File,
+				"status":           string(load.Status),
+			},
+		).
+		Observe(load.DurationInSeconds)
+
+	labels := prometheus.Labels{
+		"namespace":        load.Namespace,
+		"pipelinecoverage": load.PipelineCoverage,
+		"kind":             load.Kind,
+		"file":             load.File,
+	}
+	m.SourceParsedBytesTotal.With(labels).Add(load.ParsedBytes)
+	if load.Status == SourceLoadStatusPassed {
+		m.SourceLastSuccessfulLoadTimestamp.
+			With(labels).
+			Set(float64(load.Time.UnixNano()) / float64(time.Second))
+	}
+}
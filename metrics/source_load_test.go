@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+)
+
+func TestMetricsObserveSourceLoad(t *testing.T) {
+	m := New(&logstesting.TestLogger{T: t})
+	m.ObserveSourceLoad(&SourceLoad{
+		Namespace:         "e2e-metrics",
+		PipelineCoverage:  "gcp",
+		Kind:              "masterplan",
+		File:              ".master-plan.yml",
+		Status:            SourceLoadStatusPassed,
+		DurationInSeconds: 0.003,
+		ParsedBytes:       100,
+		Time:              time.Unix(1600000000, 0),
+	})
+	m.ObserveSourceLoad(&SourceLoad{
+		Namespace:         "e2e-metrics",
+		PipelineCoverage:  "gcp",
+		Kind:              "masterplan",
+		File:              ".master-plan.yml",
+		Status:            SourceLoadStatusFailed,
+		DurationInSeconds: 0.003,
+		ParsedBytes:       50,
+		Time:              time.Unix(1600000100, 0),
+	})
+
+	var tests = map[string]struct {
+		collector prometheus.Collector
+		expect    string
+	}{
+		"duration": {
+			collector: m.SourceLoadDurationSeconds,
+			expect: `
+# HELP e2emet_source_load_duration_seconds Time taken in seconds to load & parse a test source.
+# TYPE e2emet_source_load_duration_seconds histogram
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="0.001"} 0
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="0.002"} 0
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="0.004"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="0.008"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="0.016"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="0.032"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="0.064"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="0.128"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="0.256"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="0.512"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="1.024"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="2.048"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="4.096"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="8.192"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="16.384"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed",le="+Inf"} 1
+e2emet_source_load_duration_seconds_sum{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed"} 0.003
+e2emet_source_load_duration_seconds_count{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="failed"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="0.001"} 0
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="0.002"} 0
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="0.004"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="0.008"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="0.016"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="0.032"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="0.064"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="0.128"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="0.256"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="0.512"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="1.024"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="2.048"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="4.096"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="8.192"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="16.384"} 1
+e2emet_source_load_duration_seconds_bucket{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed",le="+Inf"} 1
+e2emet_source_load_duration_seconds_sum{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed"} 0.003
+e2emet_source_load_duration_seconds_count{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp",status="passed"} 1
+`,
+		},
+		"parsed bytes": {
+			collector: m.SourceParsedBytesTotal,
+			expect: `
+# HELP e2emet_source_parsed_bytes_total Total number of bytes read while parsing a test source.
+# TYPE e2emet_source_parsed_bytes_total counter
+e2emet_source_parsed_bytes_total{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp"} 150
+`,
+		},
+		"last successful load": {
+			collector: m.SourceLastSuccessfulLoadTimestamp,
+			expect: `
+# HELP e2emet_source_last_successful_load_timestamp_seconds Unix time in seconds of the last successful load of a test source.
+# TYPE e2emet_source_last_successful_load_timestamp_seconds gauge
+e2emet_source_last_successful_load_timestamp_seconds{file=".master-plan.yml",kind="masterplan",namespace="e2e-metrics",pipelinecoverage="gcp"} 1.6e+09
+`,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			err := testutil.CollectAndCompare(mock.collector, strings.NewReader(mock.expect))
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+		})
+	}
+}
+
+func TestMetricsObserveGitlabCIYmlLoadDuration(t *testing.T) {
+	m := New(&logstesting.TestLogger{T: t})
+	m.ObserveGitlabCIYmlLoadDuration(&GitlabCIYmlLoadDuration{
+		ValueInSeconds: 1,
+		Status:         GitlabCIYmlLoadDurationStatusPassed,
+	})
+	err := testutil.CollectAndCompare(m.GitlabCIYMLLoadDurationSeconds, strings.NewReader(`
+# HELP e2emet_gitlab_ci_yml_load_duration_seconds Time taken in seconds to load .gitlab-ci.yml.
+# TYPE e2emet_gitlab_ci_yml_load_duration_seconds summary
+e2emet_gitlab_ci_yml_load_duration_seconds{status="passed",quantile="0.5"} 1
+e2emet_gitlab_ci_yml_load_duration_seconds{status="passed",quantile="0.9"} 1
+e2emet_gitlab_ci_yml_load_duration_seconds{status="passed",quantile="0.99"} 1
+e2emet_gitlab_ci_yml_load_duration_seconds_sum{status="passed"} 1
+e2emet_gitlab_ci_yml_load_duration_seconds_count{status="passed"} 1
+`))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	// master plan summary is not touched by gitlab ci loads
+	err = testutil.CollectAndCompare(m.MasterPlanYMLLoadDurationSeconds, strings.NewReader(""))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+}
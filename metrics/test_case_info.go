@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TestCaseState is the state of a test case w.r.t the plan
+type TestCaseState string
+
+const (
+	// TestCaseStateValid indicates a planned test case that is
+	// implemented
+	TestCaseStateValid TestCaseState = "valid"
+
+	// TestCaseStateInvalid indicates an implemented test case that
+	// is not planned
+	TestCaseStateInvalid TestCaseState = "invalid"
+
+	// TestCaseStateMissing indicates a planned test case that is not
+	// implemented
+	TestCaseStateMissing TestCaseState = "missing"
+
+	// TestCaseStateDeprecated indicates a test case that is
+	// implemented with the deprecated tcid- prefix
+	TestCaseStateDeprecated TestCaseState = "deprecated"
+)
+
+const (
+	TestCaseInfoMetricName string = "test_case_info"
+
+	TestCaseInfoMetricHelp string = "Test cases with their states. Value is always 1."
+)
+
+var (
+	TestCaseInfoMetricLblNames = []string{
+		"tcid", "state", "pipelinecoverage", "group", "type", "impl",
+	}
+)
+
+// TestCaseInfo structure to populate metrics
+//
+// It exposes following metrics:
+// 	test_case_info{"tcid", "state", "pipelinecoverage", "group", "type", "impl"}
+// where
+// - tcid is the test case id
+// - state="valid|invalid|missing|deprecated"
+// - pipelinecoverage is the name of the PipelineCoverage
+// - group & type are the test/group & test/type labels of the
+// planned test. These are empty if the test case is not planned.
+// - impl="litmus|dope". It is empty if the test case is not
+// implemented.
+type TestCaseInfo struct {
+	TCID                   string
+	State                  TestCaseState
+	PipelineCoverage       string
+	Group                  string
+	Type                   string
+	TestImplementationType TestImplementationType
+}
+
+// labels returns the prometheus labels of the given info
+func (tci *TestCaseInfo) labels() prometheus.Labels {
+	return prometheus.Labels{
+		"tcid":             tci.TCID,
+		"state":            string(tci.State),
+		"pipelinecoverage": tci.PipelineCoverage,
+		"group":            tci.Group,
+		"type":             tci.Type,
+		"impl":             string(tci.TestImplementationType),
+	}
+}
+
+// SetTestCaseInfos replaces the test case infos of the given
+// PipelineCoverage with the given infos
+//
+// NOTE:
+//	Series of the PipelineCoverage that are not found in the given
+// infos are removed. Hence, test cases that disappear or change
+// their state do not linger.
+func (m *Metrics) SetTestCaseInfos(pipelineCoverage string, infos []TestCaseInfo) {
+	m.testCaseInfoLock.Lock()
+	defer m.testCaseInfoLock.Unlock()
+
+	var current = map[TestCaseInfo]bool{}
+	for _, info := range infos {
+		info.PipelineCoverage = pipelineCoverage
+		current[info] = true
+		m.TestCaseInfo.With(info.labels()).Set(1)
+	}
+	for info := range m.testCaseInfos[pipelineCoverage] {
+		if !current[info] {
+			m.TestCaseInfo.Delete(info.labels())
+		}
+	}
+	m.testCaseInfos[pipelineCoverage] = current
+}
+
+// DeleteTestCaseInfos removes the test case infos of the given
+// PipelineCoverage. This is meant to be invoked when the
+// PipelineCoverage is deleted or its config fails to load.
+func (m *Metrics) DeleteTestCaseInfos(pipelineCoverage string) {
+	m.testCaseInfoLock.Lock()
+	defer m.testCaseInfoLock.Unlock()
+
+	for info := range m.testCaseInfos[pipelineCoverage] {
+		m.TestCaseInfo.Delete(info.labels())
+	}
+	delete(m.testCaseInfos, pipelineCoverage)
+}
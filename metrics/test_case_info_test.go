@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+)
+
+func TestMetricsSetTestCaseInfos(t *testing.T) {
+	m := New(&logstesting.TestLogger{T: t})
+	m.SetTestCaseInfos("gcp", []TestCaseInfo{
+		{TCID: "TCID-A", State: TestCaseStateMissing, Group: "upgrade"},
+		{TCID: "TCID-B", State: TestCaseStateValid, Type: "functional", TestImplementationType: TestImplementationTypeLitmus},
+	})
+	m.SetTestCaseInfos("aws", []TestCaseInfo{
+		{TCID: "TCID-A", State: TestCaseStateMissing},
+	})
+	// TCID-A is implemented & TCID-B is removed from gcp
+	m.SetTestCaseInfos("gcp", []TestCaseInfo{
+		{TCID: "TCID-A", State: TestCaseStateValid, Group: "upgrade", TestImplementationType: TestImplementationTypeLitmus},
+	})
+	err := testutil.CollectAndCompare(m.TestCaseInfo, strings.NewReader(`
+# HELP e2emet_test_case_info Test cases with their states. Value is always 1.
+# TYPE e2emet_test_case_info gauge
+e2emet_test_case_info{group="",impl="",pipelinecoverage="aws",state="missing",tcid="TCID-A",type=""} 1
+e2emet_test_case_info{group="upgrade",impl="litmus",pipelinecoverage="gcp",state="valid",tcid="TCID-A",type=""} 1
+`))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+
+	m.DeleteTestCaseInfos("aws")
+	err = testutil.CollectAndCompare(m.TestCaseInfo, strings.NewReader(`
+# HELP e2emet_test_case_info Test cases with their states. Value is always 1.
+# TYPE e2emet_test_case_info gauge
+e2emet_test_case_info{group="upgrade",impl="litmus",pipelinecoverage="gcp",state="valid",tcid="TCID-A",type=""} 1
+`))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+}
@@ -16,64 +16,148 @@ limitations under the License.
 
 package metrics
 
-type ComponentUnderTest string
+import (
+	"fmt"
 
-const (
-	ComponentUnderTestDAO ComponentUnderTest = "dao"
-
-	ComponentUnderTestDirector ComponentUnderTest = "director"
-
-	ComponentUnderTestOpenEBS ComponentUnderTest = "openebs"
-)
-
-type FeatureUnderTest string
-
-const (
-	FeatureUnderTestDMAAS FeatureUnderTest = "dmaas"
-
-	FeatureUnderTestAuth FeatureUnderTest = "auth"
-
-	FeatureUnderTestTeaming FeatureUnderTest = "teaming"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-type KindUnderTest string
+type TestImplementationType string
 
 const (
-	KindUnderTestBackup KindUnderTest = "backup"
-
-	KindUnderTestRestore KindUnderTest = "restore"
-
-	KindUnderTestGoogleAuth KindUnderTest = "googleauth"
+	TestImplementationTypeLitmus TestImplementationType = "litmus"
 
-	KindUnderTestLocalAuth KindUnderTest = "localauth"
+	TestImplementationTypeDope TestImplementationType = "dope"
 )
 
-type TestImplementationType string
-
 const (
-	TestImplementationTypeLitmus TestImplementationType = "litmus"
+	// TestImplementationTypeLblName is the label of test count
+	// metrics that has the test implementation type
+	TestImplementationTypeLblName string = "testimpltype"
 
-	TestImplementationTypeDope TestImplementationType = "dope"
+	// PipelineCoverageLblName is the label of test count metrics
+	// that has the name of the PipelineCoverage
+	PipelineCoverageLblName string = "pipelinecoverage"
 )
 
 var (
-	TestCountMetricLblNames = []string{"component", "feature", "kind", "testimpltype"}
+	// TestImplementationTypes has all the supported test
+	// implementation types
+	TestImplementationTypes = []TestImplementationType{
+		TestImplementationTypeLitmus,
+		TestImplementationTypeDope,
+	}
+
+	// DefaultTestCountDimensions are the dimensions of test count
+	// metrics that are used when none are configured
+	DefaultTestCountDimensions = []string{"component", "feature", "kind"}
 )
 
+// TestCountMetricLblNames returns the label names of test count
+// metrics with the given dimensions
+func TestCountMetricLblNames(dimensions []string) []string {
+	names := append([]string(nil), dimensions...)
+	return append(names, TestImplementationTypeLblName, PipelineCoverageLblName)
+}
+
+// IsReservedTestCountLblName returns true if the given name is a
+// label of test count metrics that can not be used as a dimension
+func IsReservedTestCountLblName(name string) bool {
+	return name == TestImplementationTypeLblName || name == PipelineCoverageLblName
+}
+
 // BaseTestCount structure to populate metrics
 //
 // It exposes following metrics:
-// 	planned_test_count{"component", "feature", "kind", "testimpl"}
-// 	actual_test_count{"component", "feature", "kind", "testimpl"}
+// 	planned_test_count{<dimensions>, "testimpltype", "pipelinecoverage"}
+// 	actual_test_count{<dimensions>, "testimpltype", "pipelinecoverage"}
 // where
-// - component="director|dao|openebs"
-// - feature="dmaas|auth|teaming"
-// - kind="backup|restore|googleauth|localauth"
-// - testimpl="litmus|dope"
+// - dimensions are declared in the taxonomy of the operator & are
+//   mapped from the labels of planned tests e.g.
+//   component="director|dao|openebs"
+// - testimpltype="litmus|dope"
+// - pipelinecoverage is the name of the PipelineCoverage. It is empty
+//   if the test cases are not loaded for a PipelineCoverage.
 type BaseTestCount struct {
-	Value                  float64
-	Component              ComponentUnderTest
-	Feature                FeatureUnderTest
-	Kind                   KindUnderTest
+	Value float64
+
+	PipelineCoverage string
+
+	// Dimensions has the value of each dimension. Dimensions that
+	// are not set have empty values.
+	Dimensions map[string]string
+
 	TestImplementationType TestImplementationType
 }
+
+// labels returns the prometheus labels of this count with the
+// given dimensions
+func (btc *BaseTestCount) labels(dimensions []string) prometheus.Labels {
+	var labels = prometheus.Labels{
+		TestImplementationTypeLblName: string(btc.TestImplementationType),
+		PipelineCoverageLblName:       btc.PipelineCoverage,
+	}
+	for _, dimension := range dimensions {
+		labels[dimension] = btc.Dimensions[dimension]
+	}
+	return labels
+}
+
+// testCountSeries has the labels of the test count series that are
+// set per PipelineCoverage. Labels are mapped by their keys.
+type testCountSeries map[string]map[string]prometheus.Labels
+
+// seriesKey returns the key of the given labels
+func (m *Metrics) seriesKey(labels prometheus.Labels) string {
+	var values []string
+	for _, name := range TestCountMetricLblNames(m.testCountDimensions) {
+		values = append(values, labels[name])
+	}
+	// values are quoted to keep the keys of different values apart
+	return fmt.Sprintf("%q", values)
+}
+
+// replaceTestCounts sets the given counts of the given
+// PipelineCoverage & deletes the series of this PipelineCoverage
+// that are not found in these counts
+//
+// NOTE:
+//	Series are replaced one by one instead of resetting the gauge.
+// Hence, other PipelineCoverage(s) are not affected & a scrape does
+// not find the series missing.
+func (m *Metrics) replaceTestCounts(
+	gauge *prometheus.GaugeVec,
+	series testCountSeries,
+	pipelineCoverage string,
+	counts []*BaseTestCount,
+) {
+	m.testCountLock.Lock()
+	defer m.testCountLock.Unlock()
+
+	var current = map[string]prometheus.Labels{}
+	for _, count := range counts {
+		count.PipelineCoverage = pipelineCoverage
+		labels := count.labels(m.testCountDimensions)
+		current[m.seriesKey(labels)] = labels
+		gauge.With(labels).Set(count.Value)
+	}
+	for key, labels := range series[pipelineCoverage] {
+		if _, found := current[key]; !found {
+			gauge.Delete(labels)
+		}
+	}
+	series[pipelineCoverage] = current
+}
+
+// DeleteTestCounts removes the planned & actual test counts of the
+// given PipelineCoverage. This is meant to be invoked when the
+// PipelineCoverage is deleted.
+func (m *Metrics) DeleteTestCounts(pipelineCoverage string) {
+	m.replaceTestCounts(m.PlannedTestsTotal, m.plannedTestCountSeries, pipelineCoverage, nil)
+	m.replaceTestCounts(m.ActualTestsTotal, m.actualTestCountSeries, pipelineCoverage, nil)
+
+	m.testCountLock.Lock()
+	defer m.testCountLock.Unlock()
+	delete(m.plannedTestCountSeries, pipelineCoverage)
+	delete(m.actualTestCountSeries, pipelineCoverage)
+}
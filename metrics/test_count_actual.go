@@ -16,10 +16,6 @@ limitations under the License.
 
 package metrics
 
-import (
-	"github.com/prometheus/client_golang/prometheus"
-)
-
 const (
 	ActualTestCountMetricName string = "actual_test_count"
 
@@ -29,12 +25,12 @@ const (
 // ActualTestCount structure to populate metrics
 //
 // It exposes following metrics:
-// 	actual_test_count{"component", "feature", "kind", "testimpltype"}
+// 	actual_test_count{<dimensions>, "testimpltype", "pipelinecoverage"}
 // where
-// - component="director|dao|openebs"
-// - feature="dmaas|auth|teaming"
-// - kind="backup|restore|googleauth"
+// - dimensions are declared in the taxonomy of the operator e.g.
+//   component="director|dao|openebs"
 // - testimpltype="litmus|dope"
+// - pipelinecoverage is the name of the PipelineCoverage
 type ActualTestCount struct {
 	BaseTestCount
 }
@@ -42,13 +38,21 @@ type ActualTestCount struct {
 // SetActualTestCount sets the planned test count metric
 func (m *Metrics) SetActualTestCount(atc *ActualTestCount) {
 	m.ActualTestsTotal.
-		With(
-			prometheus.Labels{
-				"component":    string(atc.Component),
-				"feature":      string(atc.Feature),
-				"kind":         string(atc.Kind),
-				"testimpltype": string(atc.TestImplementationType),
-			},
-		).
+		With(atc.labels(m.testCountDimensions)).
 		Set(atc.Value)
 }
+
+// SetActualTestCounts replaces the actual test counts of the given
+// PipelineCoverage with the given counts. Each count is a
+// combination of dimensions.
+//
+// NOTE:
+//	Combinations of this PipelineCoverage that are not given are
+// removed
+func (m *Metrics) SetActualTestCounts(pipelineCoverage string, counts []ActualTestCount) {
+	var base []*BaseTestCount
+	for i := range counts {
+		base = append(base, &counts[i].BaseTestCount)
+	}
+	m.replaceTestCounts(m.ActualTestsTotal, m.actualTestCountSeries, pipelineCoverage, base)
+}
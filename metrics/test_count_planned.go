@@ -16,10 +16,6 @@ limitations under the License.
 
 package metrics
 
-import (
-	"github.com/prometheus/client_golang/prometheus"
-)
-
 const (
 	PlannedTestCountMetricName string = "planned_test_count"
 
@@ -29,12 +25,12 @@ const (
 // PlannedTestCount structure to populate metrics
 //
 // It exposes following metrics:
-// 	planned_test_count{"component", "feature", "kind", "testimpltype"}
+// 	planned_test_count{<dimensions>, "testimpltype", "pipelinecoverage"}
 // where
-// - component="director|dao|openebs"
-// - feature="dmaas|auth|teaming"
-// - kind="backup|restore|googleauth"
+// - dimensions are declared in the taxonomy of the operator e.g.
+//   component="director|dao|openebs"
 // - testimpltype="litmus|dope"
+// - pipelinecoverage is the name of the PipelineCoverage
 type PlannedTestCount struct {
 	BaseTestCount
 }
@@ -42,13 +38,21 @@ type PlannedTestCount struct {
 // SetPlannedTestCount sets the planned test count metric
 func (m *Metrics) SetPlannedTestCount(ptc *PlannedTestCount) {
 	m.PlannedTestsTotal.
-		With(
-			prometheus.Labels{
-				"component":    string(ptc.Component),
-				"feature":      string(ptc.Feature),
-				"kind":         string(ptc.Kind),
-				"testimpltype": string(ptc.TestImplementationType),
-			},
-		).
+		With(ptc.labels(m.testCountDimensions)).
 		Set(ptc.Value)
 }
+
+// SetPlannedTestCounts replaces the planned test counts of the given
+// PipelineCoverage with the given counts. Each count is a
+// combination of dimensions.
+//
+// NOTE:
+//	Combinations of this PipelineCoverage that are not given are
+// removed
+func (m *Metrics) SetPlannedTestCounts(pipelineCoverage string, counts []PlannedTestCount) {
+	var base []*BaseTestCount
+	for i := range counts {
+		base = append(base, &counts[i].BaseTestCount)
+	}
+	m.replaceTestCounts(m.PlannedTestsTotal, m.plannedTestCountSeries, pipelineCoverage, base)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+)
+
+func TestMetricsSetPlannedTestCounts(t *testing.T) {
+	m := NewFromConfig(Config{
+		Log:                 &logstesting.TestLogger{T: t},
+		TestCountDimensions: []string{"feature"},
+	})
+	newCount := func(feature string, value float64) PlannedTestCount {
+		return PlannedTestCount{
+			BaseTestCount: BaseTestCount{
+				Value:                  value,
+				Dimensions:             map[string]string{"feature": feature},
+				TestImplementationType: TestImplementationTypeLitmus,
+			},
+		}
+	}
+	m.SetPlannedTestCounts("gcp", []PlannedTestCount{newCount("auth", 2), newCount("dmaas", 1)})
+	m.SetPlannedTestCounts("aws", []PlannedTestCount{newCount("auth", 3)})
+	// dmaas is no longer planned in gcp
+	m.SetPlannedTestCounts("gcp", []PlannedTestCount{newCount("auth", 4)})
+
+	err := testutil.CollectAndCompare(m.PlannedTestsTotal, strings.NewReader(`
+# HELP e2emet_planned_test_count Total number of planned test cases.
+# TYPE e2emet_planned_test_count gauge
+e2emet_planned_test_count{feature="auth",pipelinecoverage="aws",testimpltype="litmus"} 3
+e2emet_planned_test_count{feature="auth",pipelinecoverage="gcp",testimpltype="litmus"} 4
+`))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+
+	m.DeleteTestCounts("aws")
+	err = testutil.CollectAndCompare(m.PlannedTestsTotal, strings.NewReader(`
+# HELP e2emet_planned_test_count Total number of planned test cases.
+# TYPE e2emet_planned_test_count gauge
+e2emet_planned_test_count{feature="auth",pipelinecoverage="gcp",testimpltype="litmus"} 4
+`))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+}
@@ -49,7 +49,7 @@ var (
 // It exposes following metrics:
 //
 // gitlab_ci_yml_load_duration_seconds{"status"}
-// - where status="loaded|failed"
+// - where status="passed|failed"
 type GitlabCIYmlLoadDuration struct {
 	ValueInSeconds float64
 	Status         GitlabCIYmlLoadDurationStatus
@@ -57,7 +57,7 @@ type GitlabCIYmlLoadDuration struct {
 
 // ObserveGitlabCIYmlLoadDuration sets the gitlab ci yaml's load duration
 func (m *Metrics) ObserveGitlabCIYmlLoadDuration(load *GitlabCIYmlLoadDuration) {
-	m.MasterPlanYMLLoadDurationSeconds.
+	m.GitlabCIYMLLoadDurationSeconds.
 		With(
 			prometheus.Labels{
 				"status": string(load.Status),
@@ -49,7 +49,7 @@ var (
 // It exposes following metrics:
 //
 // masterplan_yml_load_duration_seconds{"status"}
-// - where status="loaded|failed"
+// - where status="passed|failed"
 type MasterPlanYmlLoadDuration struct {
 	ValueInSeconds float64
 	Status         MasterPlanYmlLoadDurationStatus
@@ -0,0 +1,355 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crd generates the custom resource definitions of the
+// e2e-metrics API types
+package crd
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"mayadata.io/e2e-metrics/types"
+)
+
+const (
+	// header is written at the top of every generated file
+	header string = "# Code generated by crdgen. DO NOT EDIT.\n" +
+		"# Run 'make manifests' to regenerate.\n"
+
+	// crdTagName is the struct tag that adds validations to the
+	// schema of a field
+	crdTagName string = "crd"
+)
+
+// CustomResourceDefinition is the subset of apiextensions.k8s.io/v1
+// CustomResourceDefinition that is required by e2e-metrics
+type CustomResourceDefinition struct {
+	APIVersion string                       `json:"apiVersion"`
+	Kind       string                       `json:"kind"`
+	Metadata   ObjectMeta                   `json:"metadata"`
+	Spec       CustomResourceDefinitionSpec `json:"spec"`
+}
+
+// ObjectMeta has the identifying details of the definition
+type ObjectMeta struct {
+	Name string `json:"name"`
+}
+
+// CustomResourceDefinitionSpec describes the custom resource
+type CustomResourceDefinitionSpec struct {
+	Group    string                            `json:"group"`
+	Scope    string                            `json:"scope"`
+	Names    CustomResourceDefinitionNames     `json:"names"`
+	Versions []CustomResourceDefinitionVersion `json:"versions"`
+}
+
+// CustomResourceDefinitionNames has the names used to refer to the
+// custom resource
+type CustomResourceDefinitionNames struct {
+	Plural     string   `json:"plural"`
+	Singular   string   `json:"singular"`
+	Kind       string   `json:"kind"`
+	ListKind   string   `json:"listKind"`
+	ShortNames []string `json:"shortNames,omitempty"`
+}
+
+// CustomResourceDefinitionVersion describes a version of the custom
+// resource
+type CustomResourceDefinitionVersion struct {
+	Name                     string                   `json:"name"`
+	Served                   bool                     `json:"served"`
+	Storage                  bool                     `json:"storage"`
+	Schema                   CustomResourceValidation `json:"schema"`
+	AdditionalPrinterColumns []CustomResourceColumn   `json:"additionalPrinterColumns,omitempty"`
+}
+
+// CustomResourceValidation has the schema of a version
+type CustomResourceValidation struct {
+	OpenAPIV3Schema *JSONSchemaProps `json:"openAPIV3Schema"`
+}
+
+// CustomResourceColumn is a column shown by kubectl get
+type CustomResourceColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	JSONPath    string `json:"jsonPath"`
+	Description string `json:"description,omitempty"`
+	Priority    int32  `json:"priority,omitempty"`
+}
+
+// JSONSchemaProps is the subset of open api v3 schema that is
+// required by e2e-metrics API types
+type JSONSchemaProps struct {
+	Type                 string                      `json:"type,omitempty"`
+	Format               string                      `json:"format,omitempty"`
+	Enum                 []string                    `json:"enum,omitempty"`
+	Pattern              string                      `json:"pattern,omitempty"`
+	Minimum              *float64                    `json:"minimum,omitempty"`
+	Maximum              *float64                    `json:"maximum,omitempty"`
+	Items                *JSONSchemaProps            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchemaProps `json:"properties,omitempty"`
+	AdditionalProperties *JSONSchemaProps            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	objectMetaType = reflect.TypeOf(metav1.ObjectMeta{})
+	timeType       = reflect.TypeOf(metav1.Time{})
+)
+
+// SchemaOf returns the structural open api v3 schema of the given
+// type
+//
+// NOTE:
+//	Fields are named after their json tags. Embedded metadata is
+// kept as an object without properties since it is validated by
+// kubernetes itself.
+func SchemaOf(t reflect.Type) (*JSONSchemaProps, error) {
+	switch t {
+	case objectMetaType:
+		return &JSONSchemaProps{Type: "object"}, nil
+	case timeType:
+		return &JSONSchemaProps{Type: "string", Format: "date-time"}, nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return SchemaOf(t.Elem())
+	case reflect.String:
+		return &JSONSchemaProps{Type: "string"}, nil
+	case reflect.Bool:
+		return &JSONSchemaProps{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &JSONSchemaProps{Type: "integer", Format: "int64"}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &JSONSchemaProps{Type: "integer", Format: "int32"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchemaProps{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := SchemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchemaProps{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, errors.Errorf("Unsupported map key of %s: want string", t)
+		}
+		values, err := SchemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchemaProps{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		var props = map[string]*JSONSchemaProps{}
+		err := addProperties(t, props)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchemaProps{Type: "object", Properties: props}, nil
+	}
+	return nil, errors.Errorf("Unsupported type %s", t)
+}
+
+// addProperties adds the schema of every json field of the given
+// struct type to the given properties. Fields of inlined structs
+// are added as well.
+func addProperties(t reflect.Type, props map[string]*JSONSchemaProps) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			// unexported fields are never serialized
+			continue
+		}
+		name, opts := parseJSONTag(field)
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "inline") {
+			err := addProperties(field.Type, props)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		schema, err := SchemaOf(field.Type)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid field %s.%s", t.Name(), field.Name)
+		}
+		err = applyTag(schema, field.Tag.Get(crdTagName))
+		if err != nil {
+			return errors.Wrapf(err, "Invalid field %s.%s", t.Name(), field.Name)
+		}
+		props[name] = schema
+	}
+	return nil
+}
+
+// parseJSONTag returns the json name & the options of the given
+// field
+func parseJSONTag(field reflect.StructField) (string, string) {
+	tag := field.Tag.Get("json")
+	words := strings.SplitN(tag, ",", 2)
+	name := words[0]
+	var opts string
+	if len(words) == 2 {
+		opts = words[1]
+	}
+	if name == "" && field.Anonymous {
+		// embedded structs without a json name are inlined
+		return "", "inline"
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, opts
+}
+
+// applyTag adds the validations found in the given crd tag to the
+// given schema
+//
+// NOTE:
+//	Tag has validations separated by ';' e.g.
+// crd:"minimum=0;maximum=100". Values of enum are separated by '|'.
+func applyTag(schema *JSONSchemaProps, tag string) error {
+	if tag == "" {
+		return nil
+	}
+	for _, rule := range strings.Split(tag, ";") {
+		words := strings.SplitN(rule, "=", 2)
+		if len(words) != 2 {
+			return errors.Errorf("Invalid crd tag %q: want key=value", rule)
+		}
+		key, value := words[0], words[1]
+		switch key {
+		case "enum":
+			schema.Enum = strings.Split(value, "|")
+		case "pattern":
+			schema.Pattern = value
+		case "minimum", "maximum":
+			num, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return errors.Wrapf(err, "Invalid crd tag %q", rule)
+			}
+			if key == "minimum" {
+				schema.Minimum = &num
+			} else {
+				schema.Maximum = &num
+			}
+		default:
+			return errors.Errorf("Unsupported crd tag %q", rule)
+		}
+	}
+	return nil
+}
+
+// PipelineCoverage returns the custom resource definition of
+// PipelineCoverage
+func PipelineCoverage() (*CustomResourceDefinition, error) {
+	schema, err := SchemaOf(reflect.TypeOf(types.PipelineCoverage{}))
+	if err != nil {
+		return nil, err
+	}
+	return &CustomResourceDefinition{
+		APIVersion: "apiextensions.k8s.io/v1",
+		Kind:       "CustomResourceDefinition",
+		Metadata: ObjectMeta{
+			Name: types.PluralPipelineCoverage + "." + types.E2EMetricsMayadataGroup,
+		},
+		Spec: CustomResourceDefinitionSpec{
+			Group: types.E2EMetricsMayadataGroup,
+			Scope: "Namespaced",
+			Names: CustomResourceDefinitionNames{
+				Plural:     types.PluralPipelineCoverage,
+				Singular:   types.SingularPipelineCoverage,
+				Kind:       types.KindPipelineCoverage,
+				ListKind:   types.KindPipelineCoverage + "List",
+				ShortNames: []string{types.ShortNamePipelineCoverage},
+			},
+			Versions: []CustomResourceDefinitionVersion{
+				{
+					Name:    types.E2EMetricsVersionV1Alpha1,
+					Served:  true,
+					Storage: true,
+					Schema: CustomResourceValidation{
+						OpenAPIV3Schema: schema,
+					},
+					AdditionalPrinterColumns: []CustomResourceColumn{
+						{
+							Name:     "Phase",
+							Type:     "string",
+							JSONPath: ".result.phase",
+						},
+						{
+							Name:     "Coverage",
+							Type:     "string",
+							JSONPath: ".result.coverage",
+						},
+						{
+							Name:     "Valid",
+							Type:     "integer",
+							JSONPath: ".result.validTestCount",
+						},
+						{
+							Name:     "Invalid",
+							Type:     "integer",
+							JSONPath: ".result.invalidTestCount",
+						},
+						{
+							Name:     "Missing",
+							Type:     "integer",
+							JSONPath: ".result.missingTestCount",
+						},
+						{
+							Name:     "RunID",
+							Type:     "string",
+							JSONPath: ".result.runid",
+						},
+						{
+							Name:     "Commit",
+							Type:     "string",
+							JSONPath: ".result.commit",
+							Priority: 1,
+						},
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// Generate returns the yaml of all the custom resource definitions
+func Generate() ([]byte, error) {
+	crd, err := PipelineCoverage()
+	if err != nil {
+		return nil, err
+	}
+	data, err := yaml.Marshal(crd)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to marshal %q", crd.Metadata.Name)
+	}
+	return append([]byte(header+"---\n"), data...), nil
+}
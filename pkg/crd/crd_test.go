@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSchemaOf(t *testing.T) {
+	var zero, hundred = float64(0), float64(100)
+	type inner struct {
+		Values []string `json:"values"`
+	}
+	type embedded struct {
+		Name string `json:"name"`
+	}
+	var tests = map[string]struct {
+		obj          interface{}
+		expectSchema *JSONSchemaProps
+		isErr        bool
+	}{
+		"string": {
+			obj:          "",
+			expectSchema: &JSONSchemaProps{Type: "string"},
+		},
+		"time": {
+			obj:          metav1.Time{},
+			expectSchema: &JSONSchemaProps{Type: "string", Format: "date-time"},
+		},
+		"map of int32": {
+			obj: map[string]int32{},
+			expectSchema: &JSONSchemaProps{
+				Type:                 "object",
+				AdditionalProperties: &JSONSchemaProps{Type: "integer", Format: "int32"},
+			},
+		},
+		"struct with tags": {
+			obj: struct {
+				embedded `json:",inline"`
+				Phase    string  `json:"phase" crd:"enum=A|B"`
+				Percent  int     `json:"percent,omitempty" crd:"minimum=0;maximum=100"`
+				Inner    *inner  `json:"inner"`
+				Ignored  string  `json:"-"`
+				private  string  // unexported fields are skipped
+				Ratio    float64 `json:"ratio"`
+			}{},
+			expectSchema: &JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]*JSONSchemaProps{
+					"name":  {Type: "string"},
+					"phase": {Type: "string", Enum: []string{"A", "B"}},
+					"percent": {
+						Type:    "integer",
+						Format:  "int64",
+						Minimum: &zero,
+						Maximum: &hundred,
+					},
+					"inner": {
+						Type: "object",
+						Properties: map[string]*JSONSchemaProps{
+							"values": {
+								Type:  "array",
+								Items: &JSONSchemaProps{Type: "string"},
+							},
+						},
+					},
+					"ratio": {Type: "number"},
+				},
+			},
+		},
+		"map with int keys": {
+			obj:   map[int]string{},
+			isErr: true,
+		},
+		"invalid tag": {
+			obj: struct {
+				Count int `json:"count" crd:"minimum"`
+			}{},
+			isErr: true,
+		},
+		"unsupported tag": {
+			obj: struct {
+				Count int `json:"count" crd:"multipleOf=2"`
+			}{},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got, err := SchemaOf(reflect.TypeOf(mock.obj))
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(got, mock.expectSchema) {
+				t.Fatalf("Expected no diff got\n%s", cmp.Diff(mock.expectSchema, got))
+			}
+		})
+	}
+}
+
+// TestGenerateIsUpToDate fails if the API types are changed without
+// regenerating the custom resource definitions
+func TestGenerateIsUpToDate(t *testing.T) {
+	got, err := Generate()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	expect, err := ioutil.ReadFile("../../deploy/crd.yaml")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if string(got) != string(expect) {
+		t.Fatalf(
+			"Expected deploy/crd.yaml to be up to date: Run 'make manifests'\n%s",
+			cmp.Diff(string(expect), string(got)),
+		)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package watch notifies the changes made to the files of a config
+// directory
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultDebounce is the time to wait for more changes before
+	// notifying the changes
+	DefaultDebounce time.Duration = 500 * time.Millisecond
+
+	// kubeletDataDir is the symlink that kubelet swaps atomically
+	// to update the files of a ConfigMap volume
+	kubeletDataDir string = "..data"
+)
+
+// Config is used to create a new instance of Watcher
+type Config struct {
+	Log logr.Logger
+
+	// Path is the directory to be watched. Its sub directories are
+	// watched as well.
+	Path string
+
+	// Debounce defaults to DefaultDebounce
+	Debounce time.Duration
+
+	// OnChange is invoked once for a burst of changes
+	OnChange func()
+}
+
+// Watcher watches a config directory for changes
+//
+// NOTE:
+//	Kubelet mounts the files of a ConfigMap as symlinks to the
+// '..data' symlink which in turn points to a timestamped directory
+// e.g. '..2020_05_21_10_11_12.101'. An update creates a new
+// timestamped directory & renames a temporary symlink to '..data'.
+// Hence, only the creation of '..data' is considered as a change
+// amongst the kubelet managed entries.
+type Watcher struct {
+	log      logr.Logger
+	path     string
+	debounce time.Duration
+	onChange func()
+	watcher  *fsnotify.Watcher
+}
+
+// New returns a new instance of Watcher
+func New(conf Config) *Watcher {
+	debounce := conf.Debounce
+	if debounce == 0 {
+		debounce = DefaultDebounce
+	}
+	return &Watcher{
+		log:      conf.Log,
+		path:     conf.Path,
+		debounce: debounce,
+		onChange: conf.OnChange,
+	}
+}
+
+// isKubeletManaged returns true if the given name is an entry that
+// is managed by kubelet e.g. '..data' or '..2020_05_21_10_11_12.101'
+func isKubeletManaged(name string) bool {
+	return strings.HasPrefix(filepath.Base(name), "..")
+}
+
+// isChange returns true if the given event changes the content of
+// the watched files
+func isChange(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	if isKubeletManaged(event.Name) {
+		return filepath.Base(event.Name) == kubeletDataDir &&
+			event.Op&fsnotify.Create == fsnotify.Create
+	}
+	return true
+}
+
+// addDirs watches the given directory & all its sub directories
+//
+// NOTE:
+//	Directories managed by kubelet are not watched since their
+// changes are notified via '..data'
+func (w *Watcher) addDirs(dir string) error {
+	return filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if file != dir && (isKubeletManaged(file) || info.Name() == ".git") {
+			return filepath.SkipDir
+		}
+		w.log.V(4).Info("Will watch directory", "path", file)
+		return w.watcher.Add(file)
+	})
+}
+
+// Start watches the config directory till the given channel is
+// closed
+//
+// NOTE:
+//	This blocks & hence is expected to be invoked as a goroutine
+func (w *Watcher) Start(stopCh <-chan struct{}) error {
+	var err error
+	w.watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrapf(err, "Failed to watch %q", w.path)
+	}
+	defer w.watcher.Close()
+
+	err = w.addDirs(w.path)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to watch %q", w.path)
+	}
+	w.log.V(2).Info("Watching config directory", "path", w.path)
+
+	// timer notifies the changes once no more changes are received
+	// for the debounce duration
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return nil
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !isChange(event) {
+				continue
+			}
+			w.log.V(4).Info("Config changed", "event", event.String())
+			if event.Op&fsnotify.Create == fsnotify.Create &&
+				!isKubeletManaged(event.Name) {
+				if info, statErr := os.Stat(event.Name); statErr == nil && info.IsDir() {
+					if addErr := w.addDirs(event.Name); addErr != nil {
+						w.log.Error(addErr, "Failed to watch directory", "path", event.Name)
+					}
+				}
+			}
+			timer.Reset(w.debounce)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			// errors are logged since watching can continue
+			w.log.Error(err, "Failed to watch config", "path", w.path)
+		case <-timer.C:
+			w.log.V(2).Info("Will reload config", "path", w.path)
+			w.onChange()
+		}
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	logstesting "mayadata.io/e2e-metrics/pkg/logs/testing"
+)
+
+func TestIsChange(t *testing.T) {
+	var tests = map[string]struct {
+		event    fsnotify.Event
+		isChange bool
+	}{
+		"write to a file": {
+			event:    fsnotify.Event{Name: "/etc/config/.gitlab-ci.yml", Op: fsnotify.Write},
+			isChange: true,
+		},
+		"remove a file": {
+			event:    fsnotify.Event{Name: "/etc/config/.gitlab-ci.yml", Op: fsnotify.Remove},
+			isChange: true,
+		},
+		"chmod a file": {
+			event: fsnotify.Event{Name: "/etc/config/.gitlab-ci.yml", Op: fsnotify.Chmod},
+		},
+		"kubelet swaps data": {
+			event:    fsnotify.Event{Name: "/etc/config/..data", Op: fsnotify.Create},
+			isChange: true,
+		},
+		"kubelet creates temporary data": {
+			event: fsnotify.Event{Name: "/etc/config/..data_tmp", Op: fsnotify.Create},
+		},
+		"kubelet removes old data": {
+			event: fsnotify.Event{Name: "/etc/config/..2020_05_21_10_11_12.101", Op: fsnotify.Remove},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := isChange(mock.event)
+			if got != mock.isChange {
+				t.Fatalf("Expected change %t got %t", mock.isChange, got)
+			}
+		})
+	}
+}
+
+// mountConfigMap creates the files of a ConfigMap volume the way
+// kubelet does i.e. via a timestamped directory & a '..data' symlink
+func mountConfigMap(t *testing.T, dir, version string, files map[string]string) {
+	dataDir := filepath.Join(dir, "..2020_05_21_"+version)
+	err := os.Mkdir(dataDir, 0755)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dataDir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+	}
+	tmpLink := filepath.Join(dir, "..data_tmp")
+	err = os.Symlink(filepath.Base(dataDir), tmpLink)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	err = os.Rename(tmpLink, filepath.Join(dir, kubeletDataDir))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	for name := range files {
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); err == nil {
+			continue
+		}
+		err = os.Symlink(filepath.Join(kubeletDataDir, name), link)
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+	}
+}
+
+func TestWatcherStart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "e2e-metrics-watch")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	mountConfigMap(t, dir, "v1", map[string]string{".master-plan.yml": "v1"})
+
+	changes := make(chan struct{}, 10)
+	w := New(Config{
+		Log:      &logstesting.TestLogger{T: t},
+		Path:     dir,
+		Debounce: 50 * time.Millisecond,
+		OnChange: func() {
+			changes <- struct{}{}
+		},
+	})
+	stopCh := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- w.Start(stopCh)
+	}()
+	// wait for the watcher to be set up
+	time.Sleep(100 * time.Millisecond)
+
+	expectChange := func(reason string) {
+		select {
+		case <-changes:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected change on %s got none", reason)
+		}
+	}
+
+	mountConfigMap(t, dir, "v2", map[string]string{".master-plan.yml": "v2"})
+	expectChange("symlink swap")
+	data, err := ioutil.ReadFile(filepath.Join(dir, ".master-plan.yml"))
+	if err != nil || string(data) != "v2" {
+		t.Fatalf("Expected swapped content v2 got %q: %v", data, err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, ".gitlab-ci.yml"), []byte("v1"), 0644)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	expectChange("new file")
+
+	close(stopCh)
+	if err := <-done; err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	select {
+	case <-changes:
+		t.Fatalf("Expected changes to be debounced got more")
+	default:
+	}
+}
+
+func TestWatcherStartMissingDir(t *testing.T) {
+	w := New(Config{
+		Log:      &logstesting.TestLogger{T: t},
+		Path:     "testdata/missing",
+		OnChange: func() {},
+	})
+	err := w.Start(make(chan struct{}))
+	if err == nil {
+		t.Fatalf("Expected error got none")
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// writeCSV renders one row per test case & state
+//
+// NOTE:
+//	A test case is rendered more than once if it has more than one
+// state e.g. valid as well as duplicate
+func writeCSV(w io.Writer, r *Report) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"tcid", "state"})
+	if err != nil {
+		return err
+	}
+	for _, group := range r.testGroups() {
+		for _, tcid := range group.Tests {
+			err = writer.Write([]string{tcid, group.State})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"html/template"
+	"io"
+)
+
+// htmlTemplate renders a report as a static HTML page without any
+// external assets
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Report.Title }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: right; }
+.missing, .invalid { color: #b00; }
+.valid { color: #070; }
+</style>
+</head>
+<body>
+<h1>{{ .Report.Title }}</h1>
+<p><strong>{{ .Report.CoveragePercent }}</strong> of planned tests are implemented ({{ len .Report.ValidTests }}/{{ .Report.PlannedTestCount }})</p>
+{{- with .Report.PipelineID }}
+<p>Pipeline: <code>{{ . }}</code></p>
+{{- end }}
+{{- with .Report.RunID }}
+<p>Run: <code>{{ . }}</code></p>
+{{- end }}
+{{- with .Report.Commit }}
+<p>Commit: <code>{{ . }}</code></p>
+{{- end }}
+<table>
+<tr>{{ range .Groups }}<th>{{ .Title }}</th>{{ end }}</tr>
+<tr>{{ range .Groups }}<td>{{ len .Tests }}</td>{{ end }}</tr>
+</table>
+{{- range .Groups }}
+{{- if .Tests }}
+<h2>{{ .Title }} tests</h2>
+<ul class="{{ .State }}">
+{{- range .Tests }}
+<li><code>{{ . }}</code></li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}
+{{- with .Report.Warnings }}
+<h2>Warnings</h2>
+<ul>
+{{- range . }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+</body>
+</html>
+`))
+
+// writeHTML renders the given report as a static HTML page
+func writeHTML(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, struct {
+		Report *Report
+		Groups []testGroup
+	}{
+		Report: r,
+		Groups: r.testGroups(),
+	})
+}
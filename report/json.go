@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// nonNil returns an empty list if the given list is nil. This
+// renders an empty JSON array instead of null.
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// writeJSON renders the given report as indented JSON
+func writeJSON(w io.Writer, r *Report) error {
+	out := *r
+	out.ValidTests = nonNil(r.ValidTests)
+	out.InvalidTests = nonNil(r.InvalidTests)
+	out.MissingTests = nonNil(r.MissingTests)
+	out.DeprecatedTests = nonNil(r.DeprecatedTests)
+	out.DuplicateTests = nonNil(r.DuplicateTests)
+	out.Warnings = nonNil(r.Warnings)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
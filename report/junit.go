@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite has a JUnit test case per planned test case
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a planned test case
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+// junitFailure marks a planned test case that is not implemented
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// writeJUnit renders each planned test case as a JUnit test case.
+// Test cases that are not implemented are failed.
+func writeJUnit(w io.Writer, r *Report) error {
+	suite := junitTestSuite{
+		Name:  r.Title(),
+		Tests: len(r.ValidTests) + len(r.MissingTests),
+	}
+	className := "coverage"
+	if r.PipelineID != "" {
+		className = "coverage." + r.PipelineID
+	}
+	var missing = map[string]bool{}
+	for _, tcid := range r.MissingTests {
+		missing[tcid] = true
+	}
+	for _, tcid := range r.PlannedTests() {
+		testCase := junitTestCase{
+			Name:      tcid,
+			ClassName: className,
+		}
+		if missing[tcid] {
+			testCase.Failure = &junitFailure{Message: "Test case is planned but not implemented"}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// markdownWriter writes markdown & remembers the first error
+type markdownWriter struct {
+	w   io.Writer
+	err error
+}
+
+// printf writes the given formatted line unless an earlier write
+// failed
+func (m *markdownWriter) printf(format string, args ...interface{}) {
+	if m.err != nil {
+		return
+	}
+	_, m.err = fmt.Fprintf(m.w, format+"\n", args...)
+}
+
+// writeMarkdown renders the given report as Markdown e.g. to be
+// posted as a merge request comment
+//
+// NOTE:
+//	Sections of test cases are rendered only if these have any
+// test case
+func writeMarkdown(w io.Writer, r *Report) error {
+	m := &markdownWriter{w: w}
+	groups := r.testGroups()
+
+	m.printf("## %s", r.Title())
+	m.printf("")
+	m.printf(
+		"**%s** of planned tests are implemented (%d/%d)",
+		r.CoveragePercent(),
+		len(r.ValidTests),
+		r.PlannedTestCount,
+	)
+	m.printf("")
+
+	var titles, separators, counts []string
+	for _, group := range groups {
+		titles = append(titles, group.Title)
+		separators = append(separators, "---:")
+		counts = append(counts, fmt.Sprint(len(group.Tests)))
+	}
+	m.printf("| %s |", strings.Join(titles, " | "))
+	m.printf("| %s |", strings.Join(separators, " | "))
+	m.printf("| %s |", strings.Join(counts, " | "))
+
+	var details = []struct {
+		name  string
+		value string
+	}{
+		{"Pipeline", r.PipelineID},
+		{"Run", r.RunID},
+		{"Commit", r.Commit},
+	}
+	var hasDetails bool
+	for _, detail := range details {
+		if detail.value == "" {
+			continue
+		}
+		if !hasDetails {
+			m.printf("")
+			hasDetails = true
+		}
+		m.printf("- %s: `%s`", detail.name, detail.value)
+	}
+
+	for _, group := range groups {
+		if len(group.Tests) == 0 {
+			continue
+		}
+		m.printf("")
+		m.printf("### %s tests", group.Title)
+		m.printf("")
+		for _, tcid := range group.Tests {
+			m.printf("- `%s`", tcid)
+		}
+	}
+	if len(r.Warnings) != 0 {
+		m.printf("")
+		m.printf("### Warnings")
+		m.printf("")
+		for _, warning := range r.Warnings {
+			m.printf("- %s", warning)
+		}
+	}
+	return m.err
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package report renders a calculated coverage in formats that
+// are meant to be consumed outside of Kubernetes e.g. by CI jobs,
+// merge request comments & spreadsheets.
+package report
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FormatJSON renders the report as JSON
+	FormatJSON string = "json"
+
+	// FormatMarkdown renders the report as Markdown e.g. to be
+	// posted as a merge request comment
+	FormatMarkdown string = "markdown"
+
+	// FormatHTML renders the report as a static HTML page
+	FormatHTML string = "html"
+
+	// FormatCSV renders one row per test case
+	FormatCSV string = "csv"
+
+	// FormatJUnit renders each planned test case as a JUnit test
+	// case that fails if it is not implemented
+	FormatJUnit string = "junit"
+)
+
+// Report has the test cases of a calculated coverage
+type Report struct {
+	// Name of the PipelineCoverage if any
+	Name string `json:"name,omitempty"`
+
+	// PipelineID & RunID identify the pipeline run if any
+	PipelineID string `json:"pipelineID,omitempty"`
+	RunID      string `json:"runID,omitempty"`
+
+	// Commit is the SHA of the git commit that the test cases are
+	// loaded from if any
+	Commit string `json:"commit,omitempty"`
+
+	// Coverage is the ratio of valid to planned test cases e.g.
+	// 0.5 for 50%
+	Coverage         float64 `json:"coverage"`
+	PlannedTestCount int     `json:"plannedTestCount"`
+
+	ValidTests      []string `json:"validTests"`
+	InvalidTests    []string `json:"invalidTests"`
+	MissingTests    []string `json:"missingTests"`
+	DeprecatedTests []string `json:"deprecatedTests"`
+	DuplicateTests  []string `json:"duplicateTests"`
+	Warnings        []string `json:"warnings"`
+}
+
+// CoveragePercent returns the coverage in percent notation e.g. 50%
+func (r *Report) CoveragePercent() string {
+	return fmt.Sprintf("%d%%", int(math.Round(r.Coverage*100)))
+}
+
+// Title returns the title of this report
+func (r *Report) Title() string {
+	if r.Name == "" {
+		return "Coverage"
+	}
+	return "Coverage of " + r.Name
+}
+
+// PlannedTests returns the sorted test cases that are planned i.e.
+// the valid as well as the missing test cases
+func (r *Report) PlannedTests() []string {
+	var planned []string
+	planned = append(planned, r.ValidTests...)
+	planned = append(planned, r.MissingTests...)
+	sort.Strings(planned)
+	return planned
+}
+
+// testGroup has the test cases of a specific state
+type testGroup struct {
+	// State is the machine readable state e.g. missing
+	State string
+
+	// Title is the human readable state e.g. Missing
+	Title string
+
+	Tests []string
+}
+
+// testGroups returns the test cases of this report grouped by their
+// states in the order these are rendered
+func (r *Report) testGroups() []testGroup {
+	return []testGroup{
+		{"valid", "Valid", r.ValidTests},
+		{"invalid", "Invalid", r.InvalidTests},
+		{"missing", "Missing", r.MissingTests},
+		{"deprecated", "Deprecated", r.DeprecatedTests},
+		{"duplicate", "Duplicate", r.DuplicateTests},
+	}
+}
+
+// format renders a report in a specific format
+type format struct {
+	// extension of the files of this format
+	extension string
+	write     func(w io.Writer, r *Report) error
+}
+
+var formats = map[string]format{
+	FormatJSON:     {extension: ".json", write: writeJSON},
+	FormatMarkdown: {extension: ".md", write: writeMarkdown},
+	FormatHTML:     {extension: ".html", write: writeHTML},
+	FormatCSV:      {extension: ".csv", write: writeCSV},
+	FormatJUnit:    {extension: ".xml", write: writeJUnit},
+}
+
+// Formats returns the sorted list of supported formats
+func Formats() []string {
+	var names []string
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getFormat returns the format with the given name
+func getFormat(name string) (format, error) {
+	f, found := formats[name]
+	if !found {
+		return format{}, errors.Errorf(
+			"Unsupported report format %q: want one of %v", name, Formats(),
+		)
+	}
+	return f, nil
+}
+
+// IsFormat returns true if the given format is supported
+func IsFormat(name string) bool {
+	_, found := formats[name]
+	return found
+}
+
+// Write renders the given report in the given format
+func Write(w io.Writer, formatName string, r *Report) error {
+	f, err := getFormat(formatName)
+	if err != nil {
+		return err
+	}
+	return f.write(w, r)
+}
+
+// FileName returns the name of the file that a report with the
+// given name is written to in the given format
+func FileName(name, formatName string) (string, error) {
+	f, err := getFormat(formatName)
+	if err != nil {
+		return "", err
+	}
+	return name + f.extension, nil
+}
+
+// WriteFiles renders the given report in each of the given formats
+// & writes these to the given directory. Files are named after the
+// given name.
+//
+// NOTE:
+//	Each file is replaced atomically. Hence, readers never see a
+// partially written report.
+func WriteFiles(dir, name string, formatNames []string, r *Report) error {
+	for _, formatName := range formatNames {
+		fileName, err := FileName(name, formatName)
+		if err != nil {
+			return err
+		}
+		err = writeFile(filepath.Join(dir, fileName), formatName, r)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to write %s report %q", formatName, fileName)
+		}
+	}
+	return nil
+}
+
+// writeFile renders the given report to a temporary file that is
+// then renamed to the given file
+func writeFile(file, formatName string, r *Report) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	err = Write(tmp, formatName, r)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// FormatList is a list of report formats that can be set as a command
+// line flag
+type FormatList []string
+
+// String implements flag.Value interface
+func (l *FormatList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set implements flag.Value interface
+func (l *FormatList) Set(value string) error {
+	if _, err := getFormat(value); err != nil {
+		return err
+	}
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestReport returns a report with test cases of every state
+func newTestReport() *Report {
+	return &Report{
+		Name:             "gcp",
+		PipelineID:       "101",
+		Commit:           "abc",
+		Coverage:         .5,
+		PlannedTestCount: 2,
+		ValidTests:       []string{"TCID-A"},
+		InvalidTests:     []string{"TCID-<X>"},
+		MissingTests:     []string{"TCID-B"},
+		Warnings:         []string{"TCID-A & TCID-B share a job"},
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var tests = map[string]struct {
+		format string
+		expect string
+		isErr  bool
+	}{
+		"json": {
+			format: FormatJSON,
+			expect: `{
+  "name": "gcp",
+  "pipelineID": "101",
+  "commit": "abc",
+  "coverage": 0.5,
+  "plannedTestCount": 2,
+  "validTests": [
+    "TCID-A"
+  ],
+  "invalidTests": [
+    "TCID-<X>"
+  ],
+  "missingTests": [
+    "TCID-B"
+  ],
+  "deprecatedTests": [],
+  "duplicateTests": [],
+  "warnings": [
+    "TCID-A & TCID-B share a job"
+  ]
+}
+`,
+		},
+		"markdown": {
+			format: FormatMarkdown,
+			expect: "## Coverage of gcp\n" +
+				"\n" +
+				"**50%** of planned tests are implemented (1/2)\n" +
+				"\n" +
+				"| Valid | Invalid | Missing | Deprecated | Duplicate |\n" +
+				"| ---: | ---: | ---: | ---: | ---: |\n" +
+				"| 1 | 1 | 1 | 0 | 0 |\n" +
+				"\n" +
+				"- Pipeline: `101`\n" +
+				"- Commit: `abc`\n" +
+				"\n" +
+				"### Valid tests\n" +
+				"\n" +
+				"- `TCID-A`\n" +
+				"\n" +
+				"### Invalid tests\n" +
+				"\n" +
+				"- `TCID-<X>`\n" +
+				"\n" +
+				"### Missing tests\n" +
+				"\n" +
+				"- `TCID-B`\n" +
+				"\n" +
+				"### Warnings\n" +
+				"\n" +
+				"- TCID-A & TCID-B share a job\n",
+		},
+		"csv": {
+			format: FormatCSV,
+			expect: `tcid,state
+TCID-A,valid
+TCID-<X>,invalid
+TCID-B,missing
+`,
+		},
+		"junit": {
+			format: FormatJUnit,
+			expect: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="Coverage of gcp" tests="2" failures="1">
+    <testcase name="TCID-A" classname="coverage.101"></testcase>
+    <testcase name="TCID-B" classname="coverage.101">
+      <failure message="Test case is planned but not implemented"></failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		"unsupported format": {
+			format: "pdf",
+			isErr:  true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var got bytes.Buffer
+			err := Write(&got, mock.format, newTestReport())
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if got.String() != mock.expect {
+				t.Fatalf("Expected no diff got\n%s", cmp.Diff(mock.expect, got.String()))
+			}
+		})
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var got bytes.Buffer
+	err := Write(&got, FormatHTML, newTestReport())
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	for _, expect := range []string{
+		"<title>Coverage of gcp</title>",
+		"<strong>50%</strong> of planned tests are implemented (1/2)",
+		`<ul class="missing">` + "\n<li><code>TCID-B</code></li>",
+		"<li><code>TCID-&lt;X&gt;</code></li>",
+		"<li>TCID-A &amp; TCID-B share a job</li>",
+	} {
+		if !strings.Contains(got.String(), expect) {
+			t.Fatalf("Expected %q in\n%s", expect, got.String())
+		}
+	}
+	if strings.Contains(got.String(), "Deprecated tests") {
+		t.Fatalf("Expected no deprecated tests in\n%s", got.String())
+	}
+}
+
+func TestWriteJUnitIsValidXML(t *testing.T) {
+	var got bytes.Buffer
+	report := newTestReport()
+	report.Name = `"quoted" & <tagged>`
+	err := Write(&got, FormatJUnit, report)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	var suites junitTestSuites
+	err = xml.Unmarshal(got.Bytes(), &suites)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if suites.Suites[0].Name != "Coverage of "+report.Name {
+		t.Fatalf("Expected suite name %q got %q", "Coverage of "+report.Name, suites.Suites[0].Name)
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "e2e-metrics-report")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = WriteFiles(dir, "gcp", Formats(), newTestReport())
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	var got []string
+	for _, file := range files {
+		got = append(got, file.Name())
+	}
+	expect := []string{"gcp.csv", "gcp.html", "gcp.json", "gcp.md", "gcp.xml"}
+	if !cmp.Equal(got, expect) {
+		t.Fatalf("Expected no diff got\n%s", cmp.Diff(expect, got))
+	}
+
+	err = WriteFiles(filepath.Join(dir, "missing"), "gcp", []string{FormatJSON}, newTestReport())
+	if err == nil {
+		t.Fatalf("Expected error got none")
+	}
+}
+
+func TestFormatListSet(t *testing.T) {
+	var formats FormatList
+	err := formats.Set(FormatJUnit)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	err = formats.Set("pdf")
+	if err == nil {
+		t.Fatalf("Expected error got none")
+	}
+	if formats.String() != FormatJUnit {
+		t.Fatalf("Expected %q got %q", FormatJUnit, formats.String())
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ToUnstructured converts the given PipelineCoverage into its
+// unstructured form. APIVersion & Kind are set if these are not
+// set in the given instance.
+//
+// NOTE:
+//	Null creationTimestamp is removed from metadata since it is
+// never set by a client
+func (c *PipelineCoverage) ToUnstructured() (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(c)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "Failed to convert %s %q to unstructured", KindPipelineCoverage, c.Name,
+		)
+	}
+	if ts, found := obj["metadata"].(map[string]interface{})["creationTimestamp"]; found &&
+		ts == nil {
+		unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	if u.GetAPIVersion() == "" {
+		u.SetAPIVersion(E2EMetricsMayadataV1Alpha1)
+	}
+	if u.GetKind() == "" {
+		u.SetKind(KindPipelineCoverage)
+	}
+	return u, nil
+}
+
+// PipelineCoverageFromUnstructured converts the given unstructured
+// instance into PipelineCoverage
+func PipelineCoverageFromUnstructured(u *unstructured.Unstructured) (*PipelineCoverage, error) {
+	if u == nil || u.Object == nil {
+		return nil, errors.Errorf("Failed to convert to %s: Nil unstructured", KindPipelineCoverage)
+	}
+	if u.GetKind() != KindPipelineCoverage {
+		return nil, errors.Errorf(
+			"Failed to convert to %s: Invalid kind %q", KindPipelineCoverage, u.GetKind(),
+		)
+	}
+	var coverage = &PipelineCoverage{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, coverage)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "Failed to convert %q to %s", u.GetName(), KindPipelineCoverage,
+		)
+	}
+	return coverage, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPipelineCoverageToUnstructured(t *testing.T) {
+	coverage := &PipelineCoverage{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "e2e",
+			Namespace: "e2e-metrics",
+		},
+		Spec: PipelineCoverageSpec{
+			Pipeline: PipelineSpec{ID: "101"},
+			Test:     TestSpec{Count: 4},
+		},
+		Result: PipelineCoverageResult{
+			Phase:            PipelineCoveragePassed,
+			RunID:            "1001",
+			ValidTestCount:   3,
+			InvalidTestCount: 1,
+			MissingTestCount: 1,
+			MissingTests:     []string{"TCID-BACKUP"},
+			Coverage:         "75%",
+		},
+	}
+	var expect = &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": E2EMetricsMayadataV1Alpha1,
+			"kind":       KindPipelineCoverage,
+			"metadata": map[string]interface{}{
+				"name":      "e2e",
+				"namespace": "e2e-metrics",
+			},
+			"spec": map[string]interface{}{
+				"pipeline": map[string]interface{}{
+					"id": "101",
+				},
+				"test": map[string]interface{}{
+					"count": int64(4),
+				},
+			},
+			"result": map[string]interface{}{
+				"phase":            "Passed",
+				"reason":           "",
+				"warning":          "",
+				"deprecated":       "",
+				"runid":            "1001",
+				"validTestCount":   int64(3),
+				"invalidTestCount": int64(1),
+				"missingTestCount": int64(1),
+				"missingTests":     []interface{}{"TCID-BACKUP"},
+				"coverage":         "75%",
+			},
+		},
+	}
+	got, err := coverage.ToUnstructured()
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Fatalf("Expected no diff got\n%s", cmp.Diff(expect, got))
+	}
+
+	back, err := PipelineCoverageFromUnstructured(got)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	coverage.APIVersion = E2EMetricsMayadataV1Alpha1
+	coverage.Kind = KindPipelineCoverage
+	if !reflect.DeepEqual(back, coverage) {
+		t.Fatalf("Expected no diff got\n%s", cmp.Diff(coverage, back))
+	}
+}
+
+func TestPipelineCoverageFromUnstructuredInvalid(t *testing.T) {
+	var tests = map[string]struct {
+		obj *unstructured.Unstructured
+	}{
+		"nil": {},
+		"invalid kind": {
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": E2EMetricsMayadataV1Alpha1,
+					"kind":       "Namespace",
+				},
+			},
+		},
+		"invalid field type": {
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": E2EMetricsMayadataV1Alpha1,
+					"kind":       KindPipelineCoverage,
+					"spec": map[string]interface{}{
+						"test": map[string]interface{}{
+							"count": "four",
+						},
+					},
+				},
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			_, err := PipelineCoverageFromUnstructured(mock.obj)
+			if err == nil {
+				t.Fatalf("Expected error got none")
+			}
+		})
+	}
+}
@@ -23,12 +23,32 @@ const (
 	// E2EMetricsMayadataGroup represent e2e-metrics within mayadata org
 	E2EMetricsMayadataGroup string = "e2e-metrics" + "." + MayadataGroup
 
+	// E2EMetricsVersionV1Alpha1 represents v1alpha1 version
+	E2EMetricsVersionV1Alpha1 string = "v1alpha1"
+
 	// E2EMetricsMayadataV1Alpha1 represents v1alpha1 api version for e2e-metrics
-	E2EMetricsMayadataV1Alpha1 string = E2EMetricsMayadataGroup + "/" + "v1alpha1"
+	E2EMetricsMayadataV1Alpha1 string = E2EMetricsMayadataGroup + "/" + E2EMetricsVersionV1Alpha1
 )
 
 const (
 	// KindPipelineCoverage represent custom resource of kind
 	// PipelineCoverage
 	KindPipelineCoverage string = "PipelineCoverage"
+
+	// KindConfigMap represent kubernetes resource of kind ConfigMap
+	KindConfigMap string = "ConfigMap"
+)
+
+const (
+	// PluralPipelineCoverage is the plural name of PipelineCoverage
+	// custom resource
+	PluralPipelineCoverage string = "pipelinecoverages"
+
+	// SingularPipelineCoverage is the singular name of
+	// PipelineCoverage custom resource
+	SingularPipelineCoverage string = "pipelinecoverage"
+
+	// ShortNamePipelineCoverage is the short name of
+	// PipelineCoverage custom resource
+	ShortNamePipelineCoverage string = "pcover"
 )
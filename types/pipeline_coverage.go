@@ -16,6 +16,10 @@ limitations under the License.
 
 package types
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 const (
 	// PipelineCoverageFailed indicates a failed pipeline coverage
 	PipelineCoverageFailed string = "Failed"
@@ -23,3 +27,221 @@ const (
 	// PipelineCoveragePassed indicates a successful pipeline coverage
 	PipelineCoveragePassed string = "Passed"
 )
+
+// PipelineCoverageConditionType is the type of a condition of
+// PipelineCoverage
+type PipelineCoverageConditionType string
+
+const (
+	// PipelineCoverageConfigLoaded is True if the plan & CI files
+	// were loaded
+	PipelineCoverageConfigLoaded PipelineCoverageConditionType = "ConfigLoaded"
+
+	// PipelineCoveragePlanValid is True if the plan has at least one
+	// test
+	PipelineCoveragePlanValid PipelineCoverageConditionType = "PlanValid"
+
+	// PipelineCoverageCoverageMet is True if the coverage is at least
+	// the minimum coverage set in the spec
+	PipelineCoverageCoverageMet PipelineCoverageConditionType = "CoverageMet"
+
+	// PipelineCoverageNoInvalidTests is True if every implemented test
+	// is planned
+	PipelineCoverageNoInvalidTests PipelineCoverageConditionType = "NoInvalidTests"
+)
+
+// ConditionStatus is the status of a condition
+type ConditionStatus string
+
+const (
+	// ConditionTrue indicates that the condition is met
+	ConditionTrue ConditionStatus = "True"
+
+	// ConditionFalse indicates that the condition is not met
+	ConditionFalse ConditionStatus = "False"
+
+	// ConditionUnknown indicates that the condition could not be
+	// evaluated e.g. since the config could not be loaded
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// PipelineCoverage has the test coverage of an e2e pipeline
+//
+// NOTE:
+//	Fields can be tagged with 'crd' to add validations to the
+// schema of the generated custom resource definition e.g.
+// crd:"enum=Passed|Failed", crd:"minimum=0" or crd:"pattern=^v[0-9]+"
+//
+// NOTE:
+//	Metac does not sync the status of an attachment. Hence, the
+// observed details are set in result instead of status.
+//
+// ref - https://github.com/AmitKumarDas/metac/issues/100
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PipelineCoverage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PipelineCoverageSpec   `json:"spec"`
+	Result PipelineCoverageResult `json:"result"`
+}
+
+// PipelineCoverageSpec has the desired details of a pipeline
+// coverage
+//
+// NOTE:
+//	Plan & CI are optional. Sources that the controller is started
+// with are used if these are not set.
+type PipelineCoverageSpec struct {
+	Pipeline PipelineSpec `json:"pipeline"`
+
+	// Plan has the sources of the planned tests e.g. .master-plan.yml
+	Plan []SourceSpec `json:"plan,omitempty"`
+
+	// CI has the sources of the implemented tests e.g. .gitlab-ci.yml
+	CI []SourceSpec `json:"ci,omitempty"`
+
+	// Git when set reads the files of the sources from a local git
+	// repository instead of the config directory
+	Git *GitSpec `json:"git,omitempty"`
+
+	// MinCoverage is the minimum percentage of planned tests that
+	// need to be implemented for CoverageMet condition to be True
+	MinCoverage int64 `json:"minCoverage,omitempty" crd:"minimum=0;maximum=100"`
+
+	Test TestSpec `json:"test"`
+}
+
+// PipelineSpec identifies the pipeline whose coverage is computed
+type PipelineSpec struct {
+	ID string `json:"id"`
+
+	// RunID identifies the current run of this pipeline
+	RunID string `json:"runid,omitempty"`
+}
+
+// GitSpec refers to a commit of a local git repository
+type GitSpec struct {
+	// Path of the repository relative to the config directory of
+	// the controller. Repository can either be bare or have a
+	// working tree.
+	Path string `json:"path"`
+
+	// Ref is a branch, a tag or a commit SHA. It defaults to HEAD.
+	Ref string `json:"ref,omitempty"`
+}
+
+// SourceSpec refers to the files that test cases are loaded from
+type SourceSpec struct {
+	// Format of the files e.g. masterplan, gitlabci, githubactions
+	Format string `json:"format"`
+
+	// Path is either a file, a directory or a glob pattern relative
+	// to the config directory of the controller. It is relative to
+	// the data of ConfigMap if ConfigMap is set.
+	Path string `json:"path,omitempty"`
+
+	// Optional source does not result in an error if no files are
+	// found
+	Optional bool `json:"optional,omitempty"`
+
+	// ConfigMap has the files of this source
+	ConfigMap *ConfigMapSourceSpec `json:"configMap,omitempty"`
+}
+
+// ConfigMapSourceSpec refers to a ConfigMap that has the files of a
+// source
+//
+// NOTE:
+//	ConfigMap is expected to be in the namespace of the
+// PipelineCoverage. Every key of this ConfigMap is a file.
+type ConfigMapSourceSpec struct {
+	Name string `json:"name"`
+
+	// Key refers to a single file of this ConfigMap. Path defaults
+	// to this key if Path is not set. Path defaults to all the keys
+	// if neither is set.
+	Key string `json:"key,omitempty"`
+}
+
+// TestSpec has the details of the planned tests
+type TestSpec struct {
+	// Count is the number of planned tests
+	Count int64 `json:"count" crd:"minimum=0"`
+}
+
+// PipelineCoverageResult has the observed coverage of a pipeline
+type PipelineCoverageResult struct {
+	// Phase is either Passed or Failed
+	Phase string `json:"phase" crd:"enum=Passed|Failed"`
+
+	// Reason has the error if the coverage could not be computed
+	Reason string `json:"reason"`
+
+	Warning    string `json:"warning"`
+	Deprecated string `json:"deprecated"`
+
+	// RunID identifies the pipeline run this coverage is computed
+	// for
+	RunID string `json:"runid"`
+
+	// Commit is the SHA of the git commit that the tests are loaded
+	// from
+	Commit string `json:"commit,omitempty"`
+
+	ValidTestCount   int64 `json:"validTestCount" crd:"minimum=0"`
+	InvalidTestCount int64 `json:"invalidTestCount" crd:"minimum=0"`
+
+	// InvalidTests has the sorted names of the implemented tests
+	// that are not planned. This is capped similar to MissingTests.
+	InvalidTests []string `json:"invalidTests,omitempty"`
+
+	// MissingTestCount is the number of planned tests that are not
+	// implemented
+	MissingTestCount int64 `json:"missingTestCount" crd:"minimum=0"`
+
+	// MissingTests has the sorted names of the planned tests that
+	// are not implemented. This is capped to keep the size of this
+	// resource bounded. MissingTestCount has the actual count.
+	MissingTests []string `json:"missingTests,omitempty"`
+
+	// Coverage is the percentage of planned tests that are
+	// implemented e.g. 80%
+	Coverage string `json:"coverage" crd:"pattern=^[0-9]+%$"`
+
+	// Conditions have the latest observations of this coverage.
+	// These can be used by tools such as kubectl wait.
+	Conditions []PipelineCoverageCondition `json:"conditions,omitempty"`
+}
+
+// PipelineCoverageCondition is an observation of a PipelineCoverage
+type PipelineCoverageCondition struct {
+	Type   PipelineCoverageConditionType `json:"type" crd:"enum=ConfigLoaded|PlanValid|CoverageMet|NoInvalidTests"`
+	Status ConditionStatus               `json:"status" crd:"enum=True|False|Unknown"`
+
+	// Reason is a CamelCase reason for the last transition of this
+	// condition
+	Reason string `json:"reason"`
+
+	// Message has the human readable details of this condition
+	Message string `json:"message"`
+
+	// LastTransitionTime is the last time the status of this
+	// condition changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// ObservedGeneration is the generation of the PipelineCoverage
+	// that this condition was set for
+	ObservedGeneration int64 `json:"observedGeneration" crd:"minimum=0"`
+}
+
+// PipelineCoverageList is a list of PipelineCoverage
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PipelineCoverageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []PipelineCoverage `json:"items"`
+}